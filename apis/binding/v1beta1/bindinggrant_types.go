@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BindingGrantFrom describes the ServiceBindings that are allowed to refer to the services
+type BindingGrantFrom struct {
+	// Namespace of the ServiceBindings allowed to refer to the services
+	Namespace string `json:"namespace"`
+}
+
+// BindingGrantTo describes the services that may be referred to
+type BindingGrantTo struct {
+	// Group of the referent.  Empty for the core API group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Kind of the referent.
+	Kind string `json:"kind"`
+
+	// Name of the referent.  All resources of the kind are granted when empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// BindingGrantSpec defines the desired state of BindingGrant
+type BindingGrantSpec struct {
+	// From is the collection of namespaces whose ServiceBindings may refer
+	// to services in the namespace of the BindingGrant
+	From []BindingGrantFrom `json:"from"`
+
+	// To is the collection of services in the namespace of the BindingGrant
+	// that may be referred to
+	To []BindingGrantTo `json:"to"`
+}
+
+//+kubebuilder:object:root=true
+
+// BindingGrant is the Schema for the bindinggrants API.
+// It permits ServiceBindings in other namespaces to refer to services
+// in the namespace of the BindingGrant.
+type BindingGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BindingGrantSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BindingGrantList contains a list of BindingGrant
+type BindingGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BindingGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BindingGrant{}, &BindingGrantList{})
+}
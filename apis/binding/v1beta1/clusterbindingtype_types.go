@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterBindingTypeSpec defines the entries expected in bindings of a type
+type ClusterBindingTypeSpec struct {
+	// Required is the collection of entries a binding of this type must contain.
+	// Values of well-known entries like `host`, `port` and `uri` are validated as well.
+	Required []string `json:"required,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// ClusterBindingType is the Schema for the clusterbindingtypes API.
+// The name of the resource is the binding type it describes and it
+// takes precedence over the built-in schema for that type.
+type ClusterBindingType struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterBindingTypeSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterBindingTypeList contains a list of ClusterBindingType
+type ClusterBindingTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterBindingType `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterBindingType{}, &ClusterBindingTypeList{})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterServiceBindingSpec defines the ServiceBinding stamped out in every selected namespace
+type ClusterServiceBindingSpec struct {
+	// NamespaceSelector selects the namespaces the ServiceBinding is created in.
+	// Every namespace is selected when empty.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Template is the spec of the ServiceBinding created in every selected
+	// namespace, with the name of the ClusterServiceBinding.  Its application
+	// usually selects the workloads with a selector.  A service in another
+	// namespace must be granted to the selected namespaces by a BindingGrant.
+	Template ServiceBindingSpec `json:"template"`
+}
+
+// ClusterServiceBindingStatus defines the observed state of ClusterServiceBinding
+type ClusterServiceBindingStatus struct {
+	// ObservedGeneration is the 'Generation' of the ClusterServiceBinding that
+	// was last processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions the latest available observations of a resource's current state.
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
+
+	// Namespaces are the namespaces the ServiceBinding is created in
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+
+// ClusterServiceBinding is the Schema for the clusterservicebindings API.
+// It creates a ServiceBinding from its template in every selected namespace,
+// keeps them in sync and deletes them when the namespace stops matching.
+type ClusterServiceBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterServiceBindingSpec   `json:"spec,omitempty"`
+	Status ClusterServiceBindingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterServiceBindingList contains a list of ClusterServiceBinding
+type ClusterServiceBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterServiceBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterServiceBinding{}, &ClusterServiceBindingList{})
+}
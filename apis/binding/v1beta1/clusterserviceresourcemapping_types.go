@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterServiceResourceMappingSecret describes a Secret referenced by the service resource
+type ClusterServiceResourceMappingSecret struct {
+	// Path is the JSONPath to the name of the Secret in the service resource.
+	// The Secret is in the namespace of the service resource.
+	Path string `json:"path"`
+
+	// Keys selects the entries of the Secret to bind.  All entries are bound when empty.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// ClusterServiceResourceMappingEntry describes a binding entry read from the service resource
+type ClusterServiceResourceMappingEntry struct {
+	// Name of the binding entry
+	Name string `json:"name"`
+
+	// Path is the JSONPath to the value in the service resource
+	Path string `json:"path"`
+}
+
+// ClusterServiceResourceMappingVersion defines the mapping for a specific version of a service resource.
+type ClusterServiceResourceMappingVersion struct {
+	// Version is the version of the service resource that this mapping is for, `*` for all versions.
+	Version string `json:"version"`
+
+	// Type is the type of the binding
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Provider is the provider of the binding
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// Host is the JSONPath to the host of the service
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Port is the JSONPath to the port of the service
+	// +optional
+	Port string `json:"port,omitempty"`
+
+	// URI is the JSONPath to the URI of the service
+	// +optional
+	URI string `json:"uri,omitempty"`
+
+	// Secrets is the collection of Secrets whose entries are bound
+	// +optional
+	Secrets []ClusterServiceResourceMappingSecret `json:"secrets,omitempty"`
+
+	// Entries is the collection of additional binding entries
+	// +optional
+	Entries []ClusterServiceResourceMappingEntry `json:"entries,omitempty"`
+}
+
+// ClusterServiceResourceMappingSpec defines the desired state of ClusterServiceResourceMapping
+type ClusterServiceResourceMappingSpec struct {
+	// Versions is the collection of versions for a given resource, with mappings.
+	Versions []ClusterServiceResourceMappingVersion `json:"versions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// ClusterServiceResourceMapping is the Schema for the clusterserviceresourcemappings API.
+// The name of the resource is `<resource>.<group>` of the service resource it maps
+// and it is used for services that do not implement the provisioned service duck type.
+type ClusterServiceResourceMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterServiceResourceMappingSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterServiceResourceMappingList contains a list of ClusterServiceResourceMapping
+type ClusterServiceResourceMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterServiceResourceMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterServiceResourceMapping{}, &ClusterServiceResourceMappingList{})
+}
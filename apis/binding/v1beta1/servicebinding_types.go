@@ -51,8 +51,123 @@ type ServiceBindingSpec struct {
 
 	// Env creates environment variables based on the Secret values
 	Env []Environment `json:"env,omitempty"`
+
+	// Mappings derive additional entries of the binding Secret from the
+	// entries of the provisioned service Secret
+	// +optional
+	Mappings []Mapping `json:"mappings,omitempty"`
+
+	// Files selects and renames the entries of the binding Secret projected
+	// as files into the application container
+	// +optional
+	Files *Files `json:"files,omitempty"`
+
+	// Sources is the collection of Secrets and ConfigMaps whose entries are
+	// merged into the binding.  The entries of the service take precedence,
+	// then the sources in the order they are listed.
+	// +optional
+	Sources []Source `json:"sources,omitempty"`
+
+	// Suspend resolves and validates the binding and reports the applications
+	// it would update in the Suspended condition, without updating them.  The
+	// binding Secret is still maintained, so the binding can be staged before
+	// the applications are cut over.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DryRun suspends the ServiceBinding and previews the changes it would make
+	// to the applications in status.previews.  The updates are sent to the API
+	// server as dry runs, so the previews report rejections by admission.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Mode is how the binding is injected into the applications.  Workload
+	// updates the pod template of the applications, Pod leaves the applications
+	// untouched and injects the binding into their Pods as they are created,
+	// through the Pod mutating webhook.  Defaults to Workload.
+	// +kubebuilder:validation:Enum=Workload;Pod
+	// +optional
+	Mode BindingMode `json:"mode,omitempty"`
+
+	// Rotation is how the applications are restarted when the binding data
+	// changes, for example when the credentials of the service are rotated
+	// +optional
+	Rotation *Rotation `json:"rotation,omitempty"`
+
+	// Rollout tracks the rollout of the applications after the binding changes
+	// them and reports it through the ApplicationRolledOut condition.  The
+	// rollout is not tracked when empty.
+	// +optional
+	Rollout *Rollout `json:"rollout,omitempty"`
+}
+
+// Rollout represents the tracking of the rollout of the applications
+type Rollout struct {
+	// ProgressDeadline is the time the applications have to roll out after the
+	// binding changes them, before the rollout is reported as failed.
+	// Defaults to 10m.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+
+	// Rollback projects the binding data from immutable snapshots, and
+	// re-points the applications at the previous snapshot when they fail to
+	// roll out after a change of the binding data.  The ServiceBinding is then
+	// reported as Degraded until the binding data changes again.  Only applies
+	// to the Workload mode and to a service referenced by name.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
 }
 
+// Rotation represents the restart policy of the applications on a change of
+// the binding data
+type Rotation struct {
+	// Strategy is how the applications are restarted.  Immediate renames the
+	// projected volume on every change of the binding Secret, which restarts
+	// the applications.  Annotation sets a hash of the binding data on the pod
+	// template, which only restarts the applications when the data changes.
+	// Never keeps the pod template unchanged and relies on the kubelet refreshing
+	// the projected volume; environment variables set from the binding still
+	// change the pod template.  Defaults to Immediate.
+	// +kubebuilder:validation:Enum=Immediate;Annotation;Never
+	// +optional
+	Strategy RotationStrategy `json:"strategy,omitempty"`
+
+	// MaxConcurrent is the number of applications restarted at once.  The
+	// other applications are restarted in later batches.  All the applications
+	// are restarted at once when 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+
+	// Delay between two batches of restarts.  Defaults to 1m.
+	// +optional
+	Delay *metav1.Duration `json:"delay,omitempty"`
+}
+
+// RotationStrategy is how the applications are restarted on a change of the binding data
+type RotationStrategy string
+
+// Values for RotationStrategy
+const (
+	// RotationImmediate restarts the applications on every change of the binding Secret
+	RotationImmediate RotationStrategy = "Immediate"
+	// RotationAnnotation restarts the applications through a hash of the binding data on the pod template
+	RotationAnnotation RotationStrategy = "Annotation"
+	// RotationNever never restarts the applications
+	RotationNever RotationStrategy = "Never"
+)
+
+// BindingMode is how a binding is injected into the applications
+type BindingMode string
+
+// Values for BindingMode
+const (
+	// BindingModeWorkload updates the pod template of the applications
+	BindingModeWorkload BindingMode = "Workload"
+	// BindingModePod injects the binding into the Pods of the applications as they are created
+	BindingModePod BindingMode = "Pod"
+)
+
 // Service represents a Provisioned Service
 // Ref. https://github.com/k8s-service-bindings/spec#provisioned-service
 type Service struct {
@@ -68,6 +183,35 @@ type Service struct {
 	// Mutually exclusive with Selector.
 	// +optional
 	Name string `json:"name"`
+
+	// Selector of the referents.  Every matching service is bound through its
+	// own binding Secret, mounted at `<name>-<service name>` under
+	// $SERVICE_BINDING_ROOT.
+	// Mutually exclusive with Name.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Namespace of the referent.  Defaults to the namespace of the ServiceBinding.
+	// A service in another namespace must be granted to the namespace of the
+	// ServiceBinding by a BindingGrant in the namespace of the service.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Port is the name or number of the port of a `v1/Service`.
+	// Defaults to the first port of the Service.
+	// +optional
+	Port string `json:"port,omitempty"`
+
+	// Scheme of the `uri` entry derived from a `v1/Service`, for example `postgresql`.
+	// The `uri` entry is not derived when empty.
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+
+	// Credentials is the Secret whose entries are bound along with the entries
+	// derived from a `v1/Service`.  The Secret is in the namespace of the Service,
+	// and a BindingGrant must cover it when that is another namespace.
+	// +optional
+	Credentials *corev1.LocalObjectReference `json:"credentials,omitempty"`
 }
 
 // Application resource to inject the binding info.
@@ -91,9 +235,24 @@ type Application struct {
 	// +optional
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 
+	// Kinds are additional kinds of referents matched by Selector, so a
+	// single ServiceBinding can bind workloads of several kinds.
+	// Requires Selector.
+	// +optional
+	Kinds []ApplicationKind `json:"kinds,omitempty"`
+
 	Containers []intstr.IntOrString `json:"containers,omitempty"`
 }
 
+// ApplicationKind identifies a kind of referents of an Application
+type ApplicationKind struct {
+	// API version of the referents.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referents.
+	Kind string `json:"kind"`
+}
+
 // ServiceBindingStatus defines the observed state of ServiceBinding
 type ServiceBindingStatus struct {
 	// ObservedGeneration is the 'Generation' of the Service that
@@ -107,6 +266,67 @@ type ServiceBindingStatus struct {
 
 	// Binding exposes the projected secret for this ServiceBinding
 	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+
+	// Mode is the binding mode in effect
+	// +optional
+	Mode BindingMode `json:"mode,omitempty"`
+
+	// Previews are the changes the ServiceBinding would make to the
+	// applications.  They are only reported for a dry run.
+	// +optional
+	Previews []ApplicationPreview `json:"previews,omitempty"`
+
+	// Snapshot refers to the immutable snapshots of the binding data projected
+	// into the applications.  It is only reported when spec.rollout.rollback is set.
+	// +optional
+	Snapshot *BindingSnapshot `json:"snapshot,omitempty"`
+}
+
+// BindingSnapshot refers to the snapshot Secrets of the binding data
+type BindingSnapshot struct {
+	// Current is the name of the snapshot projected into the applications
+	// +optional
+	Current string `json:"current,omitempty"`
+
+	// Previous is the name of the snapshot projected before Current, which
+	// the binding is rolled back to.  It is cleared once the applications
+	// rolled out Current.
+	// +optional
+	Previous string `json:"previous,omitempty"`
+
+	// RolledBackFrom is the name of the snapshot the binding was rolled back
+	// from.  It is cleared when the binding data changes again.
+	// +optional
+	RolledBackFrom string `json:"rolledBackFrom,omitempty"`
+}
+
+// ApplicationPreview represents the change a dry run of a ServiceBinding would
+// make to an application
+type ApplicationPreview struct {
+	// API version of the application.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the application.
+	Kind string `json:"kind"`
+
+	// Name of the application.
+	Name string `json:"name"`
+
+	// Patch is the JSON patch (RFC 6902) the ServiceBinding would apply to the
+	// application.  It is left out when too large.
+	// +optional
+	Patch string `json:"patch,omitempty"`
+
+	// Diff is the human readable difference between the application and its
+	// updated version, cut when too large.  The values of the environment
+	// variables bound from the binding data are redacted.
+	// +optional
+	Diff string `json:"diff,omitempty"`
+
+	// Rejection is the error returned by the API server for the dry run of the
+	// update, for example by a validating admission webhook
+	// +optional
+	Rejection string `json:"rejection,omitempty"`
 }
 
 // Environment represents a key to Secret data keys and name of the environment variable
@@ -118,10 +338,128 @@ type Environment struct {
 	Key string `json:"key"`
 }
 
+// Mapping represents an entry of the binding Secret derived from other entries
+type Mapping struct {
+	// Name of the entry in the binding Secret
+	Name string `json:"name"`
+
+	// Value is a Go template rendered with the entries of the provisioned
+	// service Secret and the mappings defined before this one, for example
+	// `jdbc:postgresql://{{ .host }}:{{ .port }}/{{ .database }}`
+	Value string `json:"value"`
+}
+
+// Files selects and renames the entries of the binding Secret projected as files.
+// The `type` and `provider` entries are always projected with their own names.
+type Files struct {
+	// Include lists the entries to project.  All entries are projected when empty.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude lists the entries not to project
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Rename projects entries under a different file name
+	// +optional
+	Rename []Rename `json:"rename,omitempty"`
+}
+
+// Source represents a Secret or ConfigMap in the namespace of the ServiceBinding
+// whose entries are merged into the binding
+type Source struct {
+	// Kind of the referent, Secret or ConfigMap
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
+
+	// Name of the referent
+	Name string `json:"name"`
+
+	// Keys selects the entries to merge.  All entries are merged when empty.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// Rename represents an entry of the binding Secret projected under a different file name
+type Rename struct {
+	// From is the name of the entry in the binding Secret
+	From string `json:"from"`
+
+	// To is the file name the entry is projected as
+	To string `json:"to"`
+}
+
 // ConditionReady specifies that the resource is ready.
 // For long-running resources.
 const ConditionReady ConditionType = "Ready"
 
+// ConditionEntriesValid specifies that the binding contains the entries
+// required for its type and that the well-known entries have valid values.
+// It is only reported for types with a known schema.
+const ConditionEntriesValid ConditionType = "EntriesValid"
+
+// ConditionSourcesMerged specifies that the entries of the sources were merged
+// into the binding without conflicting values.  It is only reported when the
+// ServiceBinding has sources.
+const ConditionSourcesMerged ConditionType = "SourcesMerged"
+
+// ConditionServiceAvailable specifies that the backing service provides a binding
+// Secret.  It is only reported while the backing service does not.
+const ConditionServiceAvailable ConditionType = "ServiceAvailable"
+
+// Reasons for ConditionServiceAvailable
+const (
+	// ReasonNotProvisionedService means the kind of the backing service does
+	// not implement the provisioned service duck type and never will
+	ReasonNotProvisionedService = "NotProvisionedService"
+	// ReasonServiceNotReady means the backing service has not exposed its binding Secret yet
+	ReasonServiceNotReady = "ServiceNotReady"
+)
+
+// ConditionApplicationKindUnknown specifies that the kind of the application
+// is not served by the cluster, for example because its CustomResourceDefinition
+// is not installed yet.  It is only reported while the kind is unknown.
+const ConditionApplicationKindUnknown ConditionType = "ApplicationKindUnknown"
+
+// ConditionSuspended specifies that the applications are not updated, because
+// the ServiceBinding is suspended or the controller runs in audit-only mode.
+// The message lists the applications that would be updated.  It is only
+// reported while the binding is suspended.
+const ConditionSuspended ConditionType = "Suspended"
+
+// Reasons for ConditionSuspended
+const (
+	// ReasonSuspended means spec.suspend is set on the ServiceBinding
+	ReasonSuspended = "Suspended"
+	// ReasonAuditOnly means the controller runs with --audit-only
+	ReasonAuditOnly = "AuditOnly"
+	// ReasonDryRun means spec.dryRun is set on the ServiceBinding
+	ReasonDryRun = "DryRun"
+)
+
+// ConditionApplicationRolledOut specifies that the applications changed by the
+// binding have rolled out.  It is only reported when spec.rollout is set.
+const ConditionApplicationRolledOut ConditionType = "ApplicationRolledOut"
+
+// Reasons for ConditionApplicationRolledOut
+const (
+	// ReasonRolledOut means every application has rolled out
+	ReasonRolledOut = "RolledOut"
+	// ReasonRolloutInProgress means some applications have not rolled out yet
+	ReasonRolloutInProgress = "RolloutInProgress"
+	// ReasonRolloutFailed means some applications failed to roll out or did
+	// not roll out within the progress deadline
+	ReasonRolloutFailed = "RolloutFailed"
+)
+
+// ConditionDegraded specifies that the binding was rolled back to the previous
+// snapshot of the binding data after the applications failed to roll out.  It
+// is only reported while the binding is rolled back.
+const ConditionDegraded ConditionType = "Degraded"
+
+// ReasonRolledBack is the reason for ConditionDegraded
+const ReasonRolledBack = "RolledBack"
+
 // Values for ConditionReady
 const (
 	ConditionTrue    ConditionStatus = "True"
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ conversion.Convertible = &ClusterWorkloadResourceMapping{}
+
+// ConvertTo converts this ClusterWorkloadResourceMapping to the Hub version (v1beta1).
+// The container name and annotations paths have no v1beta1 equivalent and
+// are dropped.
+func (src *ClusterWorkloadResourceMapping) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*bindingv1beta1.ClusterApplicationResourceMapping)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", dstRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Versions = nil
+	for _, v := range src.Spec.Versions {
+		ver := bindingv1beta1.ClusterApplicationResourceMappingVersion{
+			Version: v.Version,
+			Volumes: v.Volumes,
+		}
+		for _, c := range v.Containers {
+			ver.Containers = append(ver.Containers, strings.TrimSuffix(c.Path, "[*]"))
+		}
+		dst.Spec.Versions = append(dst.Spec.Versions, ver)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+// The envs and volumeMounts paths have no v1 equivalent and are dropped.
+func (dst *ClusterWorkloadResourceMapping) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*bindingv1beta1.ClusterApplicationResourceMapping)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", srcRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Versions = nil
+	for _, v := range src.Spec.Versions {
+		ver := ClusterWorkloadResourceMappingTemplate{
+			Version: v.Version,
+			Volumes: v.Volumes,
+		}
+		for _, c := range v.Containers {
+			ver.Containers = append(ver.Containers, ClusterWorkloadResourceMappingContainer{Path: c + "[*]"})
+		}
+		dst.Spec.Versions = append(dst.Spec.Versions, ver)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterWorkloadResourceMappingContainer defines the mapping for a specific fragment of a workload resource
+// to a Container-like structure.
+type ClusterWorkloadResourceMappingContainer struct {
+	// Path is the JSONPath within the workload resource that matches an
+	// existing fragment that is container-like.
+	Path string `json:"path"`
+
+	// Name is a Restricted JSONPath that references the name of the container
+	// with the container-like workload resource fragment.  If not defined,
+	// the container will be bound to without regard to its name.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// ClusterWorkloadResourceMappingTemplate defines the mapping for a specific version of a workload resource to a
+// logical PodTemplateSpec-like structure.
+type ClusterWorkloadResourceMappingTemplate struct {
+	// Version is the version of the workload resource that this mapping is for.
+	Version string `json:"version"`
+
+	// Annotations is a Restricted JSONPath that references the annotations map
+	// within the workload resource.
+	// +optional
+	Annotations string `json:"annotations,omitempty"`
+
+	// Containers is the collection of mappings to container-like fragments of
+	// the workload resource.
+	// +optional
+	Containers []ClusterWorkloadResourceMappingContainer `json:"containers,omitempty"`
+
+	// Volumes is a Restricted JSONPath that references the slice of volumes
+	// within the workload resource.
+	// +optional
+	Volumes string `json:"volumes,omitempty"`
+}
+
+// ClusterWorkloadResourceMappingSpec defines the desired state of ClusterWorkloadResourceMapping
+type ClusterWorkloadResourceMappingSpec struct {
+	// Versions is the collection of versions for a given resource, with mappings.
+	Versions []ClusterWorkloadResourceMappingTemplate `json:"versions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// ClusterWorkloadResourceMapping is the Schema for the clusterworkloadresourcemappings API.
+// The name of the resource is `<plural>.<group>` of the workload resource it maps.
+type ClusterWorkloadResourceMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterWorkloadResourceMappingSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterWorkloadResourceMappingList contains a list of ClusterWorkloadResourceMapping
+type ClusterWorkloadResourceMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterWorkloadResourceMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterWorkloadResourceMapping{}, &ClusterWorkloadResourceMappingList{})
+}
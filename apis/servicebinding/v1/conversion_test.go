@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	servicebindingv1 "github.com/kubepreset/kubepreset/apis/servicebinding/v1"
+)
+
+var _ = Describe("Conversion:", func() {
+
+	Context("When converting a servicebinding.io/v1 ServiceBinding", func() {
+
+		sb := &servicebindingv1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sb",
+				Namespace: "default",
+			},
+			Spec: servicebindingv1.ServiceBindingSpec{
+				Name: "db",
+				Type: "postgresql",
+				Workload: servicebindingv1.ServiceBindingWorkloadReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "app",
+					Containers: []string{"app"},
+				},
+				Service: servicebindingv1.ServiceBindingServiceReference{
+					APIVersion: "v1",
+					Kind:       "Secret",
+					Name:       "secret",
+				},
+				Env: []servicebindingv1.EnvMapping{{Name: "DB_HOST", Key: "host"}},
+			},
+		}
+
+		It("should map the workload to the application", func() {
+			hub := &bindingv1beta1.ServiceBinding{}
+			Expect(sb.ConvertTo(hub)).Should(Succeed())
+
+			Expect(hub.Name).To(Equal("sb"))
+			Expect(hub.Spec.Name).To(Equal("db"))
+			Expect(hub.Spec.Type).To(Equal("postgresql"))
+			Expect(hub.Spec.Application.Kind).To(Equal("Deployment"))
+			Expect(hub.Spec.Application.Name).To(Equal("app"))
+			Expect(hub.Spec.Application.Containers).To(Equal([]intstr.IntOrString{intstr.FromString("app")}))
+			Expect(hub.Spec.Service.Name).To(Equal("secret"))
+			Expect(hub.Spec.Env).To(Equal([]bindingv1beta1.Environment{{Name: "DB_HOST", Key: "host"}}))
+		})
+
+		It("should round trip through the hub", func() {
+			hub := &bindingv1beta1.ServiceBinding{}
+			Expect(sb.ConvertTo(hub)).Should(Succeed())
+
+			converted := &servicebindingv1.ServiceBinding{}
+			Expect(converted.ConvertFrom(hub)).Should(Succeed())
+			Expect(converted.Spec).To(Equal(sb.Spec))
+		})
+
+		It("should carry free-form reasons in the condition message", func() {
+			hub := &bindingv1beta1.ServiceBinding{
+				Status: bindingv1beta1.ServiceBindingStatus{
+					Conditions: bindingv1beta1.Conditions{{
+						Type:   bindingv1beta1.ConditionReady,
+						Status: bindingv1beta1.ConditionFalse,
+						Reason: "unable to retrieve application",
+					}},
+					Binding: nil,
+				},
+			}
+
+			converted := &servicebindingv1.ServiceBinding{}
+			Expect(converted.ConvertFrom(hub)).Should(Succeed())
+			Expect(converted.Status.Conditions).To(HaveLen(1))
+			Expect(converted.Status.Conditions[0].Reason).To(Equal("NotReady"))
+			Expect(converted.Status.Conditions[0].Message).To(Equal("unable to retrieve application"))
+		})
+	})
+
+	Context("When converting a ClusterWorkloadResourceMapping", func() {
+
+		It("should map the container paths to the ClusterApplicationResourceMapping", func() {
+			cwrm := &servicebindingv1.ClusterWorkloadResourceMapping{
+				ObjectMeta: metav1.ObjectMeta{Name: "cronjobs.batch"},
+				Spec: servicebindingv1.ClusterWorkloadResourceMappingSpec{
+					Versions: []servicebindingv1.ClusterWorkloadResourceMappingTemplate{{
+						Version: "*",
+						Containers: []servicebindingv1.ClusterWorkloadResourceMappingContainer{
+							{Path: ".spec.jobTemplate.spec.template.spec.containers[*]", Name: ".name"},
+						},
+						Volumes: ".spec.jobTemplate.spec.template.spec.volumes",
+					}},
+				},
+			}
+
+			arm := &bindingv1beta1.ClusterApplicationResourceMapping{}
+			Expect(cwrm.ConvertTo(arm)).Should(Succeed())
+			Expect(arm.Name).To(Equal("cronjobs.batch"))
+			Expect(arm.Spec.Versions).To(HaveLen(1))
+			Expect(arm.Spec.Versions[0].Containers).To(Equal([]string{".spec.jobTemplate.spec.template.spec.containers"}))
+			Expect(arm.Spec.Versions[0].Volumes).To(Equal(".spec.jobTemplate.spec.template.spec.volumes"))
+		})
+	})
+})
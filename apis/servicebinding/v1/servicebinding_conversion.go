@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// conditionReasonRegexp is the format metav1.Condition requires for reasons
+var conditionReasonRegexp = regexp.MustCompile(`^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$`)
+
+var _ conversion.Convertible = &ServiceBinding{}
+
+// ConvertTo converts this ServiceBinding to the Hub version (v1beta1)
+func (src *ServiceBinding) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*bindingv1beta1.ServiceBinding)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", dstRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Name = src.Spec.Name
+	dst.Spec.Type = src.Spec.Type
+	dst.Spec.Provider = src.Spec.Provider
+
+	dst.Spec.Application = &bindingv1beta1.Application{
+		APIVersion: src.Spec.Workload.APIVersion,
+		Kind:       src.Spec.Workload.Kind,
+		Name:       src.Spec.Workload.Name,
+		Selector:   src.Spec.Workload.Selector,
+	}
+	for _, c := range src.Spec.Workload.Containers {
+		dst.Spec.Application.Containers = append(dst.Spec.Application.Containers, intstr.FromString(c))
+	}
+
+	dst.Spec.Service = &bindingv1beta1.Service{
+		APIVersion: src.Spec.Service.APIVersion,
+		Kind:       src.Spec.Service.Kind,
+		Name:       src.Spec.Service.Name,
+	}
+
+	dst.Spec.Env = nil
+	for _, e := range src.Spec.Env {
+		dst.Spec.Env = append(dst.Spec.Env, bindingv1beta1.Environment{Name: e.Name, Key: e.Key})
+	}
+
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Binding = nil
+	if src.Status.Binding != nil {
+		dst.Status.Binding = &corev1.LocalObjectReference{Name: src.Status.Binding.Name}
+	}
+	dst.Status.Conditions = nil
+	for _, c := range src.Status.Conditions {
+		reason := c.Reason
+		if c.Message != "" {
+			reason = c.Message
+		}
+		dst.Status.Conditions = append(dst.Status.Conditions, bindingv1beta1.Condition{
+			Type:               bindingv1beta1.ConditionType(c.Type),
+			Status:             bindingv1beta1.ConditionStatus(c.Status),
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             reason,
+		})
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version
+func (dst *ServiceBinding) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*bindingv1beta1.ServiceBinding)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", srcRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Name = src.Spec.Name
+	dst.Spec.Type = src.Spec.Type
+	dst.Spec.Provider = src.Spec.Provider
+
+	dst.Spec.Workload = ServiceBindingWorkloadReference{}
+	if app := src.Spec.Application; app != nil {
+		dst.Spec.Workload = ServiceBindingWorkloadReference{
+			APIVersion: app.APIVersion,
+			Kind:       app.Kind,
+			Name:       app.Name,
+			Selector:   app.Selector,
+		}
+		for _, c := range app.Containers {
+			// container indexes are not part of v1, keep them as names
+			if c.Type == intstr.Int {
+				dst.Spec.Workload.Containers = append(dst.Spec.Workload.Containers, strconv.Itoa(c.IntValue()))
+				continue
+			}
+			dst.Spec.Workload.Containers = append(dst.Spec.Workload.Containers, c.StrVal)
+		}
+	}
+
+	dst.Spec.Service = ServiceBindingServiceReference{}
+	if svc := src.Spec.Service; svc != nil {
+		dst.Spec.Service = ServiceBindingServiceReference{
+			APIVersion: svc.APIVersion,
+			Kind:       svc.Kind,
+			Name:       svc.Name,
+		}
+	}
+
+	dst.Spec.Env = nil
+	for _, e := range src.Spec.Env {
+		dst.Spec.Env = append(dst.Spec.Env, EnvMapping{Name: e.Name, Key: e.Key})
+	}
+
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Binding = nil
+	if src.Status.Binding != nil {
+		dst.Status.Binding = &ServiceBindingSecretReference{Name: src.Status.Binding.Name}
+	}
+	dst.Status.Conditions = nil
+	for _, c := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, convertCondition(c, src.Generation))
+	}
+
+	return nil
+}
+
+// convertCondition converts a v1beta1 condition to a metav1.Condition.  The
+// v1beta1 reasons are free-form sentences, so they are carried in the
+// message when they are not valid metav1.Condition reasons.
+func convertCondition(c bindingv1beta1.Condition, generation int64) metav1.Condition {
+	converted := metav1.Condition{
+		Type:               string(c.Type),
+		Status:             metav1.ConditionStatus(c.Status),
+		ObservedGeneration: generation,
+		LastTransitionTime: c.LastTransitionTime,
+		Reason:             c.Reason,
+		Message:            c.Message,
+	}
+	if conditionReasonRegexp.MatchString(c.Reason) {
+		return converted
+	}
+	switch c.Status {
+	case bindingv1beta1.ConditionTrue:
+		converted.Reason = string(c.Type)
+	case bindingv1beta1.ConditionFalse:
+		converted.Reason = "Not" + string(c.Type)
+	default:
+		converted.Reason = string(c.Type) + "Unknown"
+	}
+	switch {
+	case c.Reason != "" && c.Message != "":
+		converted.Message = c.Reason + ": " + c.Message
+	case c.Reason != "":
+		converted.Message = c.Reason
+	}
+	if converted.LastTransitionTime.IsZero() {
+		converted.LastTransitionTime = metav1.Now()
+	}
+	return converted
+}
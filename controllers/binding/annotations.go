@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BindingAnnotationPrefix is the prefix of the annotations describing the
+// binding entries of a backing service that does not implement the
+// provisioned service duck type.  The annotations are compatible with the
+// Service Binding Operator, for example:
+//
+//	service.binding/host: path={.status.host}
+//	service.binding/password: path={.status.credentials},objectType=Secret,sourceKey=password
+//	service.binding: path={.status.credentials},objectType=Secret
+//
+// The annotations may be set on the backing service resource or on its
+// CustomResourceDefinition; the ones on the resource take precedence.
+const BindingAnnotationPrefix = "service.binding"
+
+// bindingAnnotation is a parsed binding annotation
+type bindingAnnotation struct {
+	// Key is the annotation key
+	Key string
+	// Name is the binding entry, empty to import all entries of a Secret or ConfigMap
+	Name string
+	// Path is the JSONPath to the value in the backing service resource
+	Path string
+	// ObjectType is `Secret` or `ConfigMap` when the value names a resource
+	// holding the entries, empty when the value is the entry itself
+	ObjectType string
+	// SourceKey selects a single entry of the Secret or ConfigMap
+	SourceKey string
+}
+
+// BindingAnnotationErr represents the error when a binding annotation is invalid
+type BindingAnnotationErr struct {
+	Key    string
+	Reason string
+}
+
+func (e BindingAnnotationErr) Error() string {
+	return fmt.Sprintf("binding annotation %q is invalid: %s", e.Key, e.Reason)
+}
+
+// parseBindingAnnotations returns the binding annotations sorted by key
+func parseBindingAnnotations(annotations map[string]string) ([]bindingAnnotation, error) {
+	parsed := []bindingAnnotation{}
+	for key, value := range annotations {
+		var name string
+		switch {
+		case key == BindingAnnotationPrefix:
+		case strings.HasPrefix(key, BindingAnnotationPrefix+"/"):
+			name = strings.TrimPrefix(key, BindingAnnotationPrefix+"/")
+		default:
+			continue
+		}
+
+		a := bindingAnnotation{Key: key, Name: name}
+		for _, field := range strings.Split(value, ",") {
+			kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+			if len(kv) != 2 {
+				return nil, BindingAnnotationErr{Key: key, Reason: fmt.Sprintf("%q is not a key=value pair", field)}
+			}
+			switch kv[0] {
+			case "path":
+				a.Path = kv[1]
+			case "objectType":
+				a.ObjectType = kv[1]
+			case "sourceKey":
+				a.SourceKey = kv[1]
+			default:
+				return nil, BindingAnnotationErr{Key: key, Reason: fmt.Sprintf("unknown field %q", kv[0])}
+			}
+		}
+
+		switch {
+		case a.Path == "":
+			return nil, BindingAnnotationErr{Key: key, Reason: "path is required"}
+		case a.ObjectType != "" && a.ObjectType != "Secret" && a.ObjectType != "ConfigMap":
+			return nil, BindingAnnotationErr{Key: key, Reason: "objectType must be Secret or ConfigMap"}
+		case a.ObjectType == "" && a.Name == "":
+			return nil, BindingAnnotationErr{Key: key, Reason: "an entry name is required unless objectType is set"}
+		case a.ObjectType == "" && a.SourceKey != "":
+			return nil, BindingAnnotationErr{Key: key, Reason: "sourceKey requires objectType"}
+		}
+		parsed = append(parsed, a)
+	}
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i].Key < parsed[j].Key })
+	return parsed, nil
+}
+
+// annotationBindingData synthesises the binding entries from the binding
+// annotations of the backing service resource and its CustomResourceDefinition.
+// It reports false when neither carries binding annotations.
+func (r *ServiceBindingReconciler) annotationBindingData(ctx context.Context, log logr.Logger,
+	backingService *unstructured.Unstructured) (map[string][]byte, bool, error) {
+
+	annotations := map[string]string{}
+	crdAnnotations, err := r.crdAnnotations(ctx, backingService.GroupVersionKind())
+	if err != nil {
+		log.V(1).Info("unable to retrieve the CustomResourceDefinition of the backing service", "error", err)
+	}
+	for k, v := range crdAnnotations {
+		annotations[k] = v
+	}
+	for k, v := range backingService.GetAnnotations() {
+		annotations[k] = v
+	}
+
+	bindingAnnotations, err := parseBindingAnnotations(annotations)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(bindingAnnotations) == 0 {
+		return nil, false, nil
+	}
+
+	data, err := r.resolveBindingAnnotations(ctx, backingService, bindingAnnotations)
+	if err != nil {
+		return nil, false, err
+	}
+	log.V(1).Info("binding entries synthesised from the binding annotations", "annotations", len(bindingAnnotations))
+
+	return data, true, nil
+}
+
+// resolveBindingAnnotations returns the binding entries the binding annotations
+// select from the backing service resource
+func (r *ServiceBindingReconciler) resolveBindingAnnotations(ctx context.Context,
+	backingService *unstructured.Unstructured, bindingAnnotations []bindingAnnotation) (map[string][]byte, error) {
+
+	data := map[string][]byte{}
+	for _, a := range bindingAnnotations {
+		value, err := lookupPath(backingService.Object, a)
+		if err != nil {
+			return nil, err
+		}
+		if a.ObjectType == "" {
+			data[a.Name] = []byte(value)
+			continue
+		}
+
+		entries, err := r.referencedEntries(ctx, a, client.ObjectKey{Name: value, Namespace: backingService.GetNamespace()})
+		if err != nil {
+			return nil, err
+		}
+		if a.SourceKey == "" {
+			for k, v := range entries {
+				data[k] = v
+			}
+			continue
+		}
+		v, ok := entries[a.SourceKey]
+		if !ok {
+			return nil, BindingAnnotationErr{Key: a.Key,
+				Reason: fmt.Sprintf("%s %q has no entry %q", a.ObjectType, value, a.SourceKey)}
+		}
+		name := a.Name
+		if name == "" {
+			name = a.SourceKey
+		}
+		data[name] = v
+	}
+	return data, nil
+}
+
+// crdAnnotations returns the annotations of the CustomResourceDefinition of the kind
+func (r *ServiceBindingReconciler) crdAnnotations(ctx context.Context, gvk schema.GroupVersionKind) (map[string]string, error) {
+	crd, err := r.getCRD(ctx, gvk)
+	if err != nil || crd == nil {
+		return nil, err
+	}
+	return crd.GetAnnotations(), nil
+}
+
+// referencedEntries returns the entries of the Secret or ConfigMap named by a binding annotation
+func (r *ServiceBindingReconciler) referencedEntries(ctx context.Context, a bindingAnnotation,
+	key client.ObjectKey) (map[string][]byte, error) {
+
+	entries := map[string][]byte{}
+	switch a.ObjectType {
+	case "Secret":
+		secret := &corev1.Secret{}
+		if err := r.APIReader.Get(ctx, key, secret); err != nil {
+			return nil, BindingAnnotationErr{Key: a.Key, Reason: err.Error()}
+		}
+		for k, v := range secret.Data {
+			entries[k] = v
+		}
+	case "ConfigMap":
+		configMap := &corev1.ConfigMap{}
+		if err := r.APIReader.Get(ctx, key, configMap); err != nil {
+			return nil, BindingAnnotationErr{Key: a.Key, Reason: err.Error()}
+		}
+		for k, v := range configMap.BinaryData {
+			entries[k] = v
+		}
+		for k, v := range configMap.Data {
+			entries[k] = []byte(v)
+		}
+	}
+	return entries, nil
+}
+
+// lookupPath evaluates the JSONPath of the binding annotation and returns
+// the single scalar value it selects
+func lookupPath(obj map[string]interface{}, a bindingAnnotation) (string, error) {
+	jp := jsonpath.New(a.Key)
+	if err := jp.Parse(a.Path); err != nil {
+		return "", BindingAnnotationErr{Key: a.Key, Reason: err.Error()}
+	}
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return "", BindingAnnotationErr{Key: a.Key, Reason: err.Error()}
+	}
+	if len(results) != 1 || len(results[0]) != 1 {
+		return "", BindingAnnotationErr{Key: a.Key, Reason: fmt.Sprintf("path %s must select a single value", a.Path)}
+	}
+	switch v := results[0][0].Interface().(type) {
+	case map[string]interface{}, []interface{}:
+		return "", BindingAnnotationErr{Key: a.Key, Reason: fmt.Sprintf("path %s must select a scalar value", a.Path)}
+	case nil:
+		return "", BindingAnnotationErr{Key: a.Key, Reason: fmt.Sprintf("path %s selects a null value", a.Path)}
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
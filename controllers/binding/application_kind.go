@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// checkApplicationKind reports through the ApplicationKindUnknown condition
+// whether the kind of the application is served by the cluster.  The
+// ServiceBinding is reconciled again when a CustomResourceDefinition of the
+// kind is installed.
+func (r *ServiceBindingReconciler) checkApplicationKind(log logr.Logger, sb *bindingv1beta1.ServiceBinding) (bool, error) {
+	unknown := []string{}
+	for _, gvk := range applicationKinds(sb.Spec.Application) {
+		_, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			log.V(0).Info("the kind of the application is not served by the cluster", "GVK", gvk)
+			unknown = append(unknown, gvk.String())
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	if len(unknown) > 0 {
+		sb.Status.Conditions = setCondition(sb.Status.Conditions, bindingv1beta1.Condition{
+			Type:   bindingv1beta1.ConditionApplicationKindUnknown,
+			Status: bindingv1beta1.ConditionTrue,
+			Reason: "NoKindMatch",
+			Message: fmt.Sprintf("%s not served by the cluster, waiting for the CustomResourceDefinition",
+				strings.Join(unknown, ", ")),
+		})
+		return false, nil
+	}
+	sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionApplicationKindUnknown)
+	return true, nil
+}
+
+// applicationKinds returns the kinds of the application, the kind of the
+// application followed by the additional kinds matched by the selector
+func applicationKinds(app *bindingv1beta1.Application) []schema.GroupVersionKind {
+	kinds := []schema.GroupVersionKind{}
+	seen := map[schema.GroupVersionKind]bool{}
+	add := func(apiVersion, kind string) {
+		gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+		if kind == "" || seen[gvk] {
+			return
+		}
+		seen[gvk] = true
+		kinds = append(kinds, gvk)
+	}
+	add(app.APIVersion, app.Kind)
+	for _, k := range app.Kinds {
+		add(k.APIVersion, k.Kind)
+	}
+	return kinds
+}
+
+// mapCRDToServiceBinding enqueues the ServiceBindings waiting for the kind
+// defined by the CustomResourceDefinition
+func (r *ServiceBindingReconciler) mapCRDToServiceBinding(a client.Object) []reconcile.Request {
+	reply := []reconcile.Request{}
+	crd, ok := a.(*unstructured.Unstructured)
+	if !ok {
+		return reply
+	}
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+
+	serviceBindings := &bindingv1beta1.ServiceBindingList{}
+	if err := r.List(context.Background(), serviceBindings); err != nil {
+		return reply
+	}
+	for i := range serviceBindings.Items {
+		sb := &serviceBindings.Items[i]
+		if sb.Spec.Application == nil || !hasCondition(sb.Status.Conditions, bindingv1beta1.ConditionApplicationKindUnknown) {
+			continue
+		}
+		for _, gvk := range applicationKinds(sb.Spec.Application) {
+			if gvk.Group == group && gvk.Kind == kind {
+				reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: sb.Namespace,
+					Name:      sb.Name,
+				}})
+				break
+			}
+		}
+	}
+	return reply
+}
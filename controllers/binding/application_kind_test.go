@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Application Kind:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the CRD of the application is installed after the ServiceBinding", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb20",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb20", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			appCRD := &apixv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "webapps.app20.example.org",
+				}}
+			err = k8sClient.Delete(ctx, appCRD, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret20",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should report the unknown kind until the CRD is installed", func() {
+			ctx := context.Background()
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret20",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb20",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "app20.example.org/v1alpha1",
+						Kind:       "WebApp",
+						Name:       "app20",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret20",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb20", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding)
+				if err != nil {
+					return false
+				}
+				for _, c := range createdServiceBinding.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionApplicationKindUnknown && c.Status == bindingv1beta1.ConditionTrue {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			By("Creating WebApp CRD")
+			preserveUnknownFields := true
+			appCRD := &apixv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "webapps.app20.example.org",
+				},
+				Spec: apixv1.CustomResourceDefinitionSpec{
+					Group: "app20.example.org",
+					Versions: []apixv1.CustomResourceDefinitionVersion{{
+						Name:    "v1alpha1",
+						Served:  true,
+						Storage: true,
+						Schema: &apixv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apixv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apixv1.JSONSchemaProps{
+									"spec": {Type: "object", XPreserveUnknownFields: &preserveUnknownFields},
+								},
+							},
+						},
+					}},
+					Names: apixv1.CustomResourceDefinitionNames{
+						Plural: "webapps",
+						Kind:   "WebApp",
+					},
+					Scope: apixv1.NamespaceScoped,
+				}}
+			Expect(k8sClient.Create(ctx, appCRD)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding)
+				if err != nil {
+					return false
+				}
+				for _, c := range createdServiceBinding.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionApplicationKindUnknown {
+						return false
+					}
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+})
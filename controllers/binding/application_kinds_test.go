@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Application Kinds:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the application selector matches workloads of several kinds", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb21",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb21", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app21",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, deployment, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cronJob := &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job21",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, cronJob, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret21",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should bind every matched workload through the mapping of its kind", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"app.kubernetes.io/part-of": "checkout21",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret21",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			podSpec := corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyOnFailure,
+				Containers: []corev1.Container{{
+					Image: "ghcr.io/kubepreset/bindingdata:latest",
+					Name:  "bindingdata",
+				}},
+			}
+
+			By("Creating Deployment")
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app21",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: *podSpec.DeepCopy(),
+					},
+				},
+			}
+			deployment.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+			Expect(k8sClient.Create(ctx, deployment)).Should(Succeed())
+
+			By("Creating CronJob")
+			cronJob := &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job21",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: batchv1.CronJobSpec{
+					Schedule: "0 0 1 1 *",
+					JobTemplate: batchv1.JobTemplateSpec{
+						Spec: batchv1.JobSpec{
+							Template: corev1.PodTemplateSpec{
+								Spec: *podSpec.DeepCopy(),
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, cronJob)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb21",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Selector: &metav1.LabelSelector{
+							MatchLabels: matchLabels,
+						},
+						Kinds: []bindingv1beta1.ApplicationKind{
+							{APIVersion: "batch/v1", Kind: "CronJob"},
+						},
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret21",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			deploymentLookupKey := types.NamespacedName{Name: "app21", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, deploymentLookupKey, deployment); err != nil {
+					return 0
+				}
+				return len(deployment.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(1))
+
+			cronJobLookupKey := types.NamespacedName{Name: "job21", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, cronJobLookupKey, cronJob); err != nil {
+					return 0
+				}
+				return len(cronJob.Spec.JobTemplate.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(1))
+
+			Expect(cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath).
+				To(Equal("/bindings/sb21"))
+		})
+	})
+})
@@ -210,14 +210,14 @@ var _ = Describe("Application Resource Mapping:", func() {
 			}, podTimeout, podInterval).Should(BeTrue())
 
 			Expect(len(createdServiceBinding.Status.Conditions)).To(Equal(1))
-			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("secret7"))
+			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("sb7-binding"))
 
 			applicationLookupKey := types.NamespacedName{Name: sb.Spec.Application.Name, Namespace: testNamespace}
 
 			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
 			Expect(len(app.Spec.Volumes)).To(Equal(1))
 			Expect(app.Spec.Volumes[0].Name).To(HavePrefix("sb7-"))
-			Expect(app.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("secret7"))
+			Expect(app.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("sb7-binding"))
 			Expect(app.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_USERNAME", Value: "guest"}))
 			Expect(app.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_PASSWORD", Value: "password"}))
 			Expect(app.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "SERVICE_BINDING_ROOT", Value: "/bindings"}))
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+const (
+	// BindableLabel marks Secrets the manager caches and watches
+	BindableLabel = "binding.x-k8s.io/bindable"
+
+	// BindingSecretLabel marks Secrets generated for a ServiceBinding
+	BindingSecretLabel = "binding.kubepreset.dev/binding-secret"
+
+	// SourceSecretAnnotation records the namespace and name of the Secret
+	// the binding Secret was generated from
+	SourceSecretAnnotation = "binding.kubepreset.dev/source-secret"
+
+	bindingSecretSuffix = "-binding"
+)
+
+// bindingSecretName returns the name of the Secret generated for the ServiceBinding
+func bindingSecretName(sb *bindingv1beta1.ServiceBinding) string {
+	name := sb.Name
+	if len(name) > 253-len(bindingSecretSuffix) {
+		name = name[:253-len(bindingSecretSuffix)]
+	}
+	return name + bindingSecretSuffix
+}
+
+// bindingData merges the entries of the provisioned service Secret with the
+// `type` and `provider` overrides from the ServiceBinding
+func bindingData(sb *bindingv1beta1.ServiceBinding, psSecret *corev1.Secret) map[string][]byte {
+	data := make(map[string][]byte, len(psSecret.Data)+2)
+	for k, v := range psSecret.Data {
+		data[k] = v
+	}
+	if sb.Spec.Type != "" {
+		data["type"] = []byte(sb.Spec.Type)
+	}
+	if sb.Spec.Provider != "" {
+		data["provider"] = []byte(sb.Spec.Provider)
+	}
+	return data
+}
+
+// sortedKeys returns the names of the binding entries in lexical order
+func sortedKeys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reconcileBindingSecret creates or updates the Secret projected into the
+// applications.  The Secret is owned by the ServiceBinding and an existing
+// Secret with the same name that is not owned by it is never modified.
+func (r *ServiceBindingReconciler) reconcileBindingSecret(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, source types.NamespacedName, data map[string][]byte) (*corev1.Secret, error) {
+	return r.reconcileNamedBindingSecret(ctx, log, sb, bindingSecretName(sb), source, data)
+}
+
+// reconcileNamedBindingSecret creates or updates a binding Secret of the
+// ServiceBinding with the given name
+func (r *ServiceBindingReconciler) reconcileNamedBindingSecret(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, name string, source types.NamespacedName, data map[string][]byte) (*corev1.Secret, error) {
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: sb.Namespace}}
+	log.V(1).Info("creating or updating binding Secret", "Secret", secret.Name)
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		// never take over a Secret created by someone else with the same name
+		if secret.ResourceVersion != "" && !metav1.IsControlledBy(secret, sb) {
+			return OwnershipConflictErr{Kind: "Secret", Name: secret.Name}
+		}
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		for k, v := range sb.GetLabels() {
+			secret.Labels[k] = v
+		}
+		secret.Labels[BindableLabel] = "true"
+		secret.Labels[BindingSecretLabel] = "true"
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[SourceSecretAnnotation] = source.String()
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+		secret.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(sb.GetObjectMeta(), sb.GroupVersionKind())}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.V(1).Info("binding Secret reconciled", "Secret", secret.Name, "operation", op)
+
+	return secret, nil
+}
+
+// deleteBindingSecret deletes the binding Secrets generated for the ServiceBinding, if any
+func (r *ServiceBindingReconciler) deleteBindingSecret(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding) error {
+	return r.pruneBindingSecrets(ctx, log, sb, nil)
+}
+
+// pruneBindingSecrets deletes the binding Secrets generated for the
+// ServiceBinding except the ones named in keep
+func (r *ServiceBindingReconciler) pruneBindingSecrets(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, keep map[string]bool) error {
+
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.InNamespace(sb.Namespace), client.HasLabels{BindingSecretLabel}); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if keep[secret.Name] || !metav1.IsControlledBy(secret, sb) {
+			continue
+		}
+		log.V(1).Info("deleting binding Secret", "Secret", secret.Name)
+		if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
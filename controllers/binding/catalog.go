@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+//go:embed catalog/*.yaml
+var catalogFS embed.FS
+
+// coreMappings are the mappings of the built-in workload kinds whose PodSpec
+// is not at `.spec.template.spec`.  They are always enabled.
+var coreMappings = map[string]bindingv1beta1.ClusterApplicationResourceMappingSpec{
+	"cronjobs.batch": {
+		Versions: []bindingv1beta1.ClusterApplicationResourceMappingVersion{{
+			Version: "*",
+			Containers: []string{
+				".spec.jobTemplate.spec.template.spec.containers",
+				".spec.jobTemplate.spec.template.spec.initContainers",
+			},
+			Volumes: ".spec.jobTemplate.spec.template.spec.volumes",
+		}},
+	},
+}
+
+// MappingCatalog holds the enabled entries of the built-in catalog of
+// application resource mappings for well-known workload kinds.  The entries
+// are only used for kinds without a ClusterApplicationResourceMapping.
+type MappingCatalog struct {
+	mappings map[string]bindingv1beta1.ClusterApplicationResourceMappingSpec
+}
+
+// CatalogEntries returns the names of the entries of the built-in catalog
+func CatalogEntries() []string {
+	files, _ := catalogFS.ReadDir("catalog")
+	entries := make([]string, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, strings.TrimSuffix(f.Name(), path.Ext(f.Name())))
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// NewMappingCatalog returns a catalog with the given entries enabled.  The
+// entry `all` enables every entry of the built-in catalog.
+func NewMappingCatalog(enabled ...string) (*MappingCatalog, error) {
+	c := &MappingCatalog{mappings: map[string]bindingv1beta1.ClusterApplicationResourceMappingSpec{}}
+	var entries []string
+	all := false
+	for _, entry := range enabled {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "all" {
+			all = true
+			continue
+		}
+		if !containsString(CatalogEntries(), entry) {
+			return nil, fmt.Errorf("unknown mapping catalog entry %q, expected one of %s",
+				entry, strings.Join(CatalogEntries(), ", "))
+		}
+		entries = append(entries, entry)
+	}
+	if all {
+		entries = CatalogEntries()
+	}
+	for _, entry := range entries {
+		data, err := catalogFS.ReadFile(path.Join("catalog", entry+".yaml"))
+		if err != nil {
+			return nil, err
+		}
+		arm := &bindingv1beta1.ClusterApplicationResourceMapping{}
+		if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), len(data)).Decode(arm); err != nil {
+			return nil, fmt.Errorf("invalid mapping catalog entry %q: %w", entry, err)
+		}
+		c.mappings[arm.Name] = arm.Spec
+	}
+	return c, nil
+}
+
+// Lookup returns the mapping of the resource named `<resource>.<group>`.  The
+// mappings of the built-in workload kinds are always returned.
+func (c *MappingCatalog) Lookup(name string) (bindingv1beta1.ClusterApplicationResourceMappingSpec, bool) {
+	if spec, ok := coreMappings[name]; ok {
+		return spec, true
+	}
+	if c == nil {
+		return bindingv1beta1.ClusterApplicationResourceMappingSpec{}, false
+	}
+	spec, ok := c.mappings[name]
+	return spec, ok
+}
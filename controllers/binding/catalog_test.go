@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	custompod "github.com/kubepreset/custompod/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
+)
+
+var _ = Describe("Mapping Catalog:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the catalog entry of the application kind is enabled", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb19",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb19", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			app := &custompod.CustomPod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app19",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret19",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should bind the application without a ClusterApplicationResourceMapping", func() {
+			ctx := context.Background()
+
+			By("Removing any ClusterApplicationResourceMapping of the kind")
+			arm := &bindingv1beta1.ClusterApplicationResourceMapping{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "custompods.binding.kubepreset.dev",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, arm, client.GracePeriodSeconds(0))
+			Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret19",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			app := &custompod.CustomPod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app19",
+					Namespace: testNamespace,
+				},
+				Spec: custompod.CustomPodSpec{
+					Containers: []corev1.Container{{
+						Image: "ghcr.io/kubepreset/bindingdata:latest",
+						Name:  "bindingdata",
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb19",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "binding.kubepreset.dev/v1beta1",
+						Kind:       "CustomPod",
+						Name:       "app19",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret19",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			applicationLookupKey := types.NamespacedName{Name: "app19", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return len(app.Spec.Volumes)
+			}, timeout, interval).Should(Equal(1))
+
+			Expect(app.Spec.Volumes[0].Name).To(HavePrefix("sb19-"))
+			Expect(app.Spec.Containers[0].VolumeMounts[0].MountPath).To(Equal("/bindings/sb19"))
+		})
+	})
+	Context("When enabling catalog entries from a comma separated flag", func() {
+
+		It("should ignore blanks around the entries", func() {
+			catalog, err := bindingcontrollers.NewMappingCatalog(" kubepreset-custompods", "")
+			Expect(err).ShouldNot(HaveOccurred())
+			_, ok := catalog.Lookup("custompods.binding.kubepreset.dev")
+			Expect(ok).To(BeTrue())
+
+			catalog, err = bindingcontrollers.NewMappingCatalog("argo-rollouts", " all")
+			Expect(err).ShouldNot(HaveOccurred())
+			_, ok = catalog.Lookup("custompods.binding.kubepreset.dev")
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should reject unknown entries even when all entries are enabled", func() {
+			_, err := bindingcontrollers.NewMappingCatalog("foo", " all")
+			Expect(err).Should(MatchError(ContainSubstring(`unknown mapping catalog entry "foo"`)))
+		})
+	})
+})
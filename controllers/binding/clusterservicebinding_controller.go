@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// ClusterServiceBindingLabel marks the ServiceBindings created for a
+// ClusterServiceBinding, its value is the name of the ClusterServiceBinding
+const ClusterServiceBindingLabel = "binding.x-k8s.io/cluster-service-binding"
+
+// ClusterServiceBindingReconciler reconciles a ClusterServiceBinding object
+type ClusterServiceBindingReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=clusterservicebindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=clusterservicebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=servicebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile creates or updates the ServiceBinding of the ClusterServiceBinding
+// in every selected namespace and deletes it from the other namespaces
+func (r *ClusterServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("clusterservicebinding", req.NamespacedName)
+
+	csb := &bindingv1beta1.ClusterServiceBinding{}
+	if err := r.Get(ctx, req.NamespacedName, csb); err != nil {
+		// the owned ServiceBindings of a deleted ClusterServiceBinding are garbage collected
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !csb.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// every namespace is selected without a namespace selector
+	selector := labels.Everything()
+	if csb.Spec.NamespaceSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(csb.Spec.NamespaceSelector)
+		if err != nil {
+			log.Error(err, "invalid namespace selector")
+			return r.setStatus(ctx, log, csb, nil, bindingv1beta1.ConditionFalse, "invalid namespace selector: "+err.Error())
+		}
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	selected := map[string]bool{}
+	bound := []string{}
+	conflicts := []string{}
+	for _, ns := range namespaces.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		selected[ns.Name] = true
+		if err := r.reconcileServiceBinding(ctx, log, csb, ns.Name); err != nil {
+			var ownershipErr OwnershipConflictErr
+			if errors.As(err, &ownershipErr) {
+				log.Error(err, "unable to create the ServiceBinding", "namespace", ns.Name)
+				conflicts = append(conflicts, ns.Name)
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		bound = append(bound, ns.Name)
+	}
+
+	serviceBindings := &bindingv1beta1.ServiceBindingList{}
+	if err := r.List(ctx, serviceBindings, client.MatchingLabels{ClusterServiceBindingLabel: csb.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range serviceBindings.Items {
+		sb := &serviceBindings.Items[i]
+		if selected[sb.Namespace] || !metav1.IsControlledBy(sb, csb) {
+			continue
+		}
+		log.V(0).Info("deleting the ServiceBinding of a namespace no longer selected", "namespace", sb.Namespace)
+		if err := r.Delete(ctx, sb); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	sort.Strings(bound)
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		reason := "a ServiceBinding with the name of the ClusterServiceBinding exists and is not owned by it in " +
+			strings.Join(conflicts, ", ")
+		return r.setStatus(ctx, log, csb, bound, bindingv1beta1.ConditionFalse, reason)
+	}
+	return r.setStatus(ctx, log, csb, bound, bindingv1beta1.ConditionTrue, "")
+}
+
+// reconcileServiceBinding creates or updates the ServiceBinding of the
+// ClusterServiceBinding in the namespace.  A ServiceBinding with the same name
+// that is not owned by the ClusterServiceBinding is never modified.
+func (r *ClusterServiceBindingReconciler) reconcileServiceBinding(ctx context.Context, log logr.Logger,
+	csb *bindingv1beta1.ClusterServiceBinding, namespace string) error {
+
+	sb := &bindingv1beta1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: csb.Name, Namespace: namespace}}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, sb, func() error {
+		if sb.ResourceVersion != "" && !metav1.IsControlledBy(sb, csb) {
+			return OwnershipConflictErr{Kind: "ServiceBinding", Name: sb.Name}
+		}
+		if sb.Labels == nil {
+			sb.Labels = map[string]string{}
+		}
+		sb.Labels[ClusterServiceBindingLabel] = csb.Name
+		sb.Spec = *csb.Spec.Template.DeepCopy()
+		return controllerutil.SetControllerReference(csb, sb, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.V(1).Info("ServiceBinding reconciled", "namespace", namespace, "operation", op)
+	return nil
+}
+
+func (r *ClusterServiceBindingReconciler) setStatus(ctx context.Context, log logr.Logger,
+	csb *bindingv1beta1.ClusterServiceBinding, namespaces []string,
+	conditionStatus bindingv1beta1.ConditionStatus, reason string) (ctrl.Result, error) {
+
+	csb.Status.ObservedGeneration = csb.Generation
+	csb.Status.Namespaces = namespaces
+	csb.Status.Conditions = setCondition(csb.Status.Conditions, bindingv1beta1.Condition{
+		Type:   bindingv1beta1.ConditionReady,
+		Status: conditionStatus,
+		Reason: reason,
+	})
+	if err := r.Status().Update(ctx, csb); err != nil {
+		log.Error(err, "unable to update the cluster service binding status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Scheme == nil {
+		r.Scheme = mgr.GetScheme()
+	}
+
+	// every ClusterServiceBinding may select a new or relabelled namespace
+	mapNamespaceToClusterServiceBinding := func(a client.Object) []reconcile.Request {
+		reply := []reconcile.Request{}
+		csbs := &bindingv1beta1.ClusterServiceBindingList{}
+		if err := r.List(context.Background(), csbs); err != nil {
+			return reply
+		}
+		for _, csb := range csbs.Items {
+			reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{Name: csb.Name}})
+		}
+		return reply
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&bindingv1beta1.ClusterServiceBinding{}).
+		Owns(&bindingv1beta1.ServiceBinding{}).
+		Watches(&source.Kind{Type: &corev1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(mapNamespaceToClusterServiceBinding)).
+		Complete(r)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
+)
+
+var _ = Describe("Cluster Service Binding:", func() {
+
+	const (
+		timeout  = time.Second * 20
+		interval = time.Millisecond * 250
+	)
+
+	Context("When a ClusterServiceBinding selects namespaces by label", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			csb := &bindingv1beta1.ClusterServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "csb24",
+				}}
+			err := k8sClient.Delete(ctx, csb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test24",
+				}}
+			err = k8sClient.Delete(ctx, ns, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should create the ServiceBinding in the selected namespaces only", func() {
+			ctx := context.Background()
+
+			By("Creating Namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test24",
+					Labels: map[string]string{"csb24": "true"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			csb := &bindingv1beta1.ClusterServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "csb24",
+				},
+				Spec: bindingv1beta1.ClusterServiceBindingSpec{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"csb24": "true"},
+					},
+					Template: bindingv1beta1.ServiceBindingSpec{
+						Application: &bindingv1beta1.Application{
+							APIVersion: "apps/v1",
+							Kind:       "Deployment",
+							Selector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"environment": "test24"},
+							},
+						},
+						Service: &bindingv1beta1.Service{
+							APIVersion: "v1",
+							Kind:       "Secret",
+							Name:       "secret24",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, csb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "csb24", Namespace: "test24"}
+			sb := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceBindingLookupKey, sb)
+			}, timeout, interval).Should(Succeed())
+
+			Expect(sb.Labels[bindingcontrollers.ClusterServiceBindingLabel]).To(Equal("csb24"))
+			Expect(sb.Spec.Service.Name).To(Equal("secret24"))
+
+			csbLookupKey := types.NamespacedName{Name: "csb24"}
+			Eventually(func() []string {
+				if err := k8sClient.Get(ctx, csbLookupKey, csb); err != nil {
+					return nil
+				}
+				return csb.Status.Namespaces
+			}, timeout, interval).Should(ContainElement("test24"))
+
+			By("Removing the label of the Namespace")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "test24"}, ns); err != nil {
+					return err
+				}
+				delete(ns.Labels, "csb24")
+				return k8sClient.Update(ctx, ns)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, sb)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+	Context("When a ClusterServiceBinding has no namespace selector", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			csb := &bindingv1beta1.ClusterServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "csb24b",
+				}}
+			err := k8sClient.Delete(ctx, csb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// the stamped out ServiceBindings are not garbage collected by the test environment
+			serviceBindings := &bindingv1beta1.ServiceBindingList{}
+			err = k8sClient.List(ctx, serviceBindings,
+				client.MatchingLabels{bindingcontrollers.ClusterServiceBindingLabel: "csb24b"})
+			Expect(err).ShouldNot(HaveOccurred())
+			for i := range serviceBindings.Items {
+				err := k8sClient.Delete(ctx, &serviceBindings.Items[i], client.GracePeriodSeconds(0))
+				Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should create the ServiceBinding in every namespace", func() {
+			ctx := context.Background()
+
+			csb := &bindingv1beta1.ClusterServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "csb24b",
+				},
+				Spec: bindingv1beta1.ClusterServiceBindingSpec{
+					Template: bindingv1beta1.ServiceBindingSpec{
+						Application: &bindingv1beta1.Application{
+							APIVersion: "apps/v1",
+							Kind:       "Deployment",
+							Selector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"environment": "test24b"},
+							},
+						},
+						Service: &bindingv1beta1.Service{
+							APIVersion: "v1",
+							Kind:       "Secret",
+							Name:       "secret24b",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, csb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "csb24b", Namespace: "default"}
+			sb := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceBindingLookupKey, sb)
+			}, timeout, interval).Should(Succeed())
+
+			csbLookupKey := types.NamespacedName{Name: "csb24b"}
+			Eventually(func() []string {
+				if err := k8sClient.Get(ctx, csbLookupKey, csb); err != nil {
+					return nil
+				}
+				return csb.Status.Namespaces
+			}, timeout, interval).Should(ContainElements("default", "kube-system"))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// setCondition adds the condition or replaces the existing condition of the
+// same type.  LastTransitionTime is only changed when the status changes.
+func setCondition(conditions bindingv1beta1.Conditions, c bindingv1beta1.Condition) bindingv1beta1.Conditions {
+	for k, cond := range conditions {
+		if cond.Type == c.Type {
+			c.LastTransitionTime = cond.LastTransitionTime
+			if cond.Status != c.Status {
+				c.LastTransitionTime = metav1.NewTime(time.Now())
+			}
+			conditions[k] = c
+			return conditions
+		}
+	}
+	c.LastTransitionTime = metav1.NewTime(time.Now())
+	return append(conditions, c)
+}
+
+// removeCondition removes the condition of the given type
+func removeCondition(conditions bindingv1beta1.Conditions, t bindingv1beta1.ConditionType) bindingv1beta1.Conditions {
+	result := bindingv1beta1.Conditions{}
+	for _, cond := range conditions {
+		if cond.Type != t {
+			result = append(result, cond)
+		}
+	}
+	return result
+}
+
+// hasCondition reports whether a condition of the given type is set
+func hasCondition(conditions bindingv1beta1.Conditions, t bindingv1beta1.ConditionType) bool {
+	for _, cond := range conditions {
+		if cond.Type == t {
+			return true
+		}
+	}
+	return false
+}
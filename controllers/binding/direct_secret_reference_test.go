@@ -251,14 +251,14 @@ var _ = Describe("Direct Secret Reference:", func() {
 			}, podTimeout, podInterval).Should(BeTrue())
 
 			Expect(len(createdServiceBinding.Status.Conditions)).To(Equal(1))
-			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("secret1"))
+			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("sb1-binding"))
 
 			applicationLookupKey := types.NamespacedName{Name: sb.Spec.Application.Name, Namespace: testNamespace}
 
 			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
 			Expect(len(app.Spec.Template.Spec.Volumes)).To(Equal(1))
 			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(HavePrefix("sb1-"))
-			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("secret1"))
+			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("sb1-binding"))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_USERNAME", Value: "guest"}))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_PASSWORD", Value: "password"}))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "SERVICE_BINDING_ROOT", Value: "/bindings"}))
@@ -522,14 +522,14 @@ var _ = Describe("Direct Secret Reference:", func() {
 			}, podTimeout, podInterval).Should(BeTrue())
 
 			Expect(len(createdServiceBinding.Status.Conditions)).To(Equal(1))
-			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("secret2"))
+			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("sb2-binding"))
 
 			applicationLookupKey := types.NamespacedName{Name: sb.Spec.Application.Name, Namespace: testNamespace}
 
 			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
 			Expect(len(app.Spec.Template.Spec.Volumes)).To(Equal(1))
 			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(HavePrefix("sb2-"))
-			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("secret2"))
+			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("sb2-binding"))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_USERNAME", Value: "guest"}))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_PASSWORD", Value: "password"}))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "SERVICE_BINDING_ROOT", Value: "/bindings"}))
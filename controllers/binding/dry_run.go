@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-logr/logr"
+	"gomodules.xyz/jsonpatch/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// redactedValue replaces the values of the environment variables bound from
+// the binding data in the previews
+const redactedValue = "<redacted>"
+
+// maxPreviewDiff and maxPreviewPatch bound the size of the preview of an
+// application, as the previews are stored in the status of the ServiceBinding
+const (
+	maxPreviewDiff  = 4096
+	maxPreviewPatch = 8192
+)
+
+// previewApplication returns the change from the original to the updated
+// application and sends the update to the API server as a dry run, so
+// rejections by admission are reported without persisting the application.
+// The values bound from the binding data are redacted from the change.
+func (r *ServiceBindingReconciler) previewApplication(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, original, updated *unstructured.Unstructured) bindingv1beta1.ApplicationPreview {
+
+	redacted := map[string]bool{}
+	for _, e := range sb.Spec.Env {
+		redacted[e.Name] = true
+	}
+	from := original.DeepCopy()
+	redactEnv(from.Object, redacted)
+	to := updated.DeepCopy()
+	redactEnv(to.Object, redacted)
+
+	preview := bindingv1beta1.ApplicationPreview{
+		APIVersion: updated.GetAPIVersion(),
+		Kind:       updated.GetKind(),
+		Name:       updated.GetName(),
+		Diff:       diff.ObjectReflectDiff(from.Object, to.Object),
+	}
+	if len(preview.Diff) > maxPreviewDiff {
+		preview.Diff = preview.Diff[:maxPreviewDiff] + "\n... (truncated)"
+	}
+
+	patch, err := jsonPatch(from, to)
+	if err != nil {
+		log.Error(err, "unable to compute the JSON patch of the application", "application", applicationRef(updated))
+		preview.Rejection = "unable to compute the JSON patch: " + err.Error()
+		return preview
+	}
+	if len(patch) <= maxPreviewPatch {
+		// a cut JSON patch cannot be applied, it is left out instead
+		preview.Patch = patch
+	}
+
+	// the dry run overwrites the object with the response of the API server
+	if err := r.Update(ctx, updated.DeepCopy(), client.DryRunAll); err != nil {
+		log.V(0).Info("dry run of the application update rejected", "application", applicationRef(updated), "error", err.Error())
+		preview.Rejection = err.Error()
+	}
+	return preview
+}
+
+// redactEnv replaces the values of the environment variables with the given
+// names, wherever they are in the object
+func redactEnv(obj interface{}, names map[string]bool) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		if name, ok := o["name"].(string); ok && names[name] {
+			if _, ok := o["value"].(string); ok {
+				o["value"] = redactedValue
+			}
+		}
+		for _, v := range o {
+			redactEnv(v, names)
+		}
+	case []interface{}:
+		for _, v := range o {
+			redactEnv(v, names)
+		}
+	}
+}
+
+// jsonPatch returns the JSON patch from the original to the updated object
+func jsonPatch(original, updated *unstructured.Unstructured) (string, error) {
+	from, err := json.Marshal(original.Object)
+	if err != nil {
+		return "", err
+	}
+	to, err := json.Marshal(updated.Object)
+	if err != nil {
+		return "", err
+	}
+	ops, err := jsonpatch.CreatePatch(from, to)
+	if err != nil {
+		return "", err
+	}
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Dry Run:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 260
+		testNamespace = "default"
+	)
+
+	Context("When the ServiceBinding is a dry run", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb26",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb26", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app26",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret26",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should preview the changes to the application without updating it", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test26",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret26",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"username": "guest",
+					"password": "s3cr3t-26",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app26",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb26",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app26",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret26",
+					},
+					Env:    []bindingv1beta1.Environment{{Name: "DB_PASSWORD", Key: "password"}},
+					DryRun: true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb26", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return 0
+				}
+				return len(createdServiceBinding.Status.Previews)
+			}, timeout, interval).Should(Equal(1))
+
+			preview := createdServiceBinding.Status.Previews[0]
+			Expect(preview.Kind).To(Equal("Deployment"))
+			Expect(preview.Name).To(Equal("app26"))
+			Expect(preview.Patch).To(ContainSubstring("/spec/template/spec/volumes"))
+			Expect(preview.Diff).NotTo(BeEmpty())
+			Expect(preview.Rejection).To(BeEmpty())
+
+			By("Redacting the values bound from the binding data")
+			Expect(preview.Patch).To(ContainSubstring("DB_PASSWORD"))
+			Expect(preview.Patch).NotTo(ContainSubstring("s3cr3t-26"))
+			Expect(preview.Diff).NotTo(ContainSubstring("s3cr3t-26"))
+
+			applicationLookupKey := types.NamespacedName{Name: "app26", Namespace: testNamespace}
+			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
+			Expect(app.Spec.Template.Spec.Volumes).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Environment Variables:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the ServiceBinding is reconciled again", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb33",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb33", Namespace: testNamespace}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, &bindingv1beta1.ServiceBinding{})
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app33",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret33",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not update the application again", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test33",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret33",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app33",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb33",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app33",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret33",
+					},
+					Env: []bindingv1beta1.Environment{
+						{Name: "BACKING_SERVICE_USERNAME", Key: "username"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			applicationLookupKey := types.NamespacedName{Name: "app33", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return len(app.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(1))
+			generation := app.Generation
+
+			By("Changing the Secret without changing its data")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "secret33", Namespace: testNamespace}, secret)).Should(Succeed())
+			secret.Labels = map[string]string{"reconcile": "again"}
+			Expect(k8sClient.Update(ctx, secret)).Should(Succeed())
+
+			Consistently(func() int64 {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return app.Generation
+			}, time.Second*5, interval).Should(Equal(generation))
+			Expect(app.Spec.Template.Spec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+				{Name: "BACKING_SERVICE_USERNAME", Value: "guest"},
+				{Name: "SERVICE_BINDING_ROOT", Value: "/bindings"},
+			}))
+		})
+	})
+})
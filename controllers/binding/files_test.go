@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
+)
+
+var _ = Describe("Projected Files:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When creating ServiceBinding with include and rename rules", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			for _, obj := range []client.Object{
+				&bindingv1beta1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "sb10", Namespace: testNamespace}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app10", Namespace: testNamespace}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret10", Namespace: testNamespace}},
+			} {
+				err := k8sClient.Delete(ctx, obj, client.GracePeriodSeconds(0))
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb10", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should project only the selected entries under their file names", func() {
+			ctx := context.Background()
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret10",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"user":     "guest",
+					"password": "password",
+					"internal": "do-not-project",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			matchLabels := map[string]string{
+				"environment": "test10",
+			}
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app10",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb10",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app10",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret10",
+					},
+					Files: &bindingv1beta1.Files{
+						Include: []string{"user", "password"},
+						Rename:  []bindingv1beta1.Rename{{From: "user", To: "username"}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb10", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding)
+				if err != nil {
+					return false
+				}
+				for _, condition := range createdServiceBinding.Status.Conditions {
+					if condition.Type == bindingv1beta1.ConditionReady &&
+						condition.Status == bindingv1beta1.ConditionTrue {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			applicationLookupKey := types.NamespacedName{Name: "app10", Namespace: testNamespace}
+
+			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
+			Expect(len(app.Spec.Template.Spec.Volumes)).To(Equal(1))
+			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Items).To(ConsistOf(
+				corev1.KeyToPath{Key: "password", Path: "password"},
+				corev1.KeyToPath{Key: "type", Path: "type"},
+				corev1.KeyToPath{Key: "user", Path: "username"},
+			))
+		})
+	})
+	table.DescribeTable("Validating the file names of renamed entries",
+		func(p string, valid bool) {
+			Expect(bindingcontrollers.ValidFilePath(p)).To(Equal(valid))
+		},
+		table.Entry("a plain name", "username", true),
+		table.Entry("a nested name", "db/username", true),
+		table.Entry("a name with dots", "tls.crt", true),
+		table.Entry("an empty name", "", false),
+		table.Entry("an absolute path", "/etc/passwd", false),
+		table.Entry("the parent directory", "..", false),
+		table.Entry("a path escaping the directory", "../username", false),
+		table.Entry("a name starting with two dots", "..foo", false),
+		table.Entry("a path with a parent element", "a/../b", false),
+	)
+})
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// ReferenceNotGrantedErr represents the error when a service in another
+// namespace is not granted to the namespace of the ServiceBinding
+type ReferenceNotGrantedErr struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+func (e ReferenceNotGrantedErr) Error() string {
+	return fmt.Sprintf("%s %s/%s is not granted to the namespace of the ServiceBinding by a BindingGrant", e.Kind, e.Namespace, e.Name)
+}
+
+// serviceNamespace returns the namespace of the service referred by the ServiceBinding
+func serviceNamespace(sb *bindingv1beta1.ServiceBinding) string {
+	if sb.Spec.Service.Namespace != "" {
+		return sb.Spec.Service.Namespace
+	}
+	return sb.Namespace
+}
+
+// checkReferenceGrant returns ReferenceNotGrantedErr when the service is in
+// another namespace and no BindingGrant in that namespace permits the reference.
+// The credentials Secret of a `v1/Service` must be granted as well.
+func (r *ServiceBindingReconciler) checkReferenceGrant(ctx context.Context, sb *bindingv1beta1.ServiceBinding) error {
+	namespace := serviceNamespace(sb)
+	if namespace == sb.Namespace {
+		return nil
+	}
+
+	gv, err := schema.ParseGroupVersion(sb.Spec.Service.APIVersion)
+	if err != nil {
+		return err
+	}
+
+	grants := &bindingv1beta1.BindingGrantList{}
+	if err := r.List(ctx, grants, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	if !granted(grants.Items, sb.Namespace, gv.Group, sb.Spec.Service.Kind, sb.Spec.Service.Name) {
+		return ReferenceNotGrantedErr{Namespace: namespace, Kind: sb.Spec.Service.Kind, Name: sb.Spec.Service.Name}
+	}
+	if ref := sb.Spec.Service.Credentials; isKubeService(sb) && ref != nil {
+		if !granted(grants.Items, sb.Namespace, "", "Secret", ref.Name) {
+			return ReferenceNotGrantedErr{Namespace: namespace, Kind: "Secret", Name: ref.Name}
+		}
+	}
+	return nil
+}
+
+// granted reports whether one of the grants permits the namespace to refer to the resource
+func granted(grants []bindingv1beta1.BindingGrant, namespace, group, kind, name string) bool {
+	for _, grant := range grants {
+		if grantsFrom(grant, namespace) && grantsTo(grant, group, kind, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func grantsFrom(grant bindingv1beta1.BindingGrant, namespace string) bool {
+	for _, from := range grant.Spec.From {
+		if from.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func grantsTo(grant bindingv1beta1.BindingGrant, group, kind, name string) bool {
+	for _, to := range grant.Spec.To {
+		if to.Group == group && to.Kind == kind && (to.Name == "" || to.Name == name) {
+			return true
+		}
+	}
+	return false
+}
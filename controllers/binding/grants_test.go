@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Cross-Namespace Service Reference:", func() {
+
+	const (
+		timeout          = time.Second * 20
+		interval         = time.Millisecond * 250
+		testNamespace    = "default"
+		serviceNamespace = "test12-data"
+	)
+
+	Context("When the service is in another namespace", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb12",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb12", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app12",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: serviceNamespace}}
+			err = k8sClient.Delete(ctx, ns, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should bind only after a BindingGrant permits the reference", func() {
+			ctx := context.Background()
+
+			By("Creating the service namespace and Secret")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: serviceNamespace}}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret12",
+					Namespace: serviceNamespace,
+					Labels:    map[string]string{"binding.x-k8s.io/bindable": "true"},
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			matchLabels := map[string]string{
+				"environment": "test12",
+			}
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app12",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb12",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app12",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret12",
+						Namespace:  serviceNamespace,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb12", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding)
+				if err != nil {
+					return false
+				}
+				for _, condition := range createdServiceBinding.Status.Conditions {
+					if condition.Type == bindingv1beta1.ConditionReady &&
+						condition.Status == bindingv1beta1.ConditionFalse {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(createdServiceBinding.Status.Conditions[0].Reason).To(ContainSubstring("not granted"))
+
+			bindingSecretLookupKey := types.NamespacedName{Name: "sb12-binding", Namespace: testNamespace}
+			Expect(k8sClient.Get(ctx, bindingSecretLookupKey, &corev1.Secret{})).ShouldNot(Succeed())
+
+			By("Creating the BindingGrant")
+			grant := &bindingv1beta1.BindingGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "grant12",
+					Namespace: serviceNamespace,
+				},
+				Spec: bindingv1beta1.BindingGrantSpec{
+					From: []bindingv1beta1.BindingGrantFrom{{Namespace: testNamespace}},
+					To:   []bindingv1beta1.BindingGrantTo{{Kind: "Secret", Name: "secret12"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, grant)).Should(Succeed())
+
+			bindingSecret := &corev1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, bindingSecretLookupKey, bindingSecret)
+			}, timeout, interval).Should(Succeed())
+
+			Expect(string(bindingSecret.Data["username"])).To(Equal("guest"))
+			Expect(bindingSecret.Annotations["binding.kubepreset.dev/source-secret"]).To(Equal(serviceNamespace + "/secret12"))
+
+			applicationLookupKey := types.NamespacedName{Name: "app12", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return len(app.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(1))
+
+			By("Revoking the BindingGrant")
+			Expect(k8sClient.Delete(ctx, grant)).Should(Succeed())
+
+			Eventually(func() bool {
+				return k8sClient.Get(ctx, bindingSecretLookupKey, &corev1.Secret{}) != nil
+			}, timeout, interval).Should(BeTrue())
+
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return -1
+				}
+				mounts := 0
+				for _, c := range app.Spec.Template.Spec.Containers {
+					mounts += len(c.VolumeMounts)
+				}
+				return len(app.Spec.Template.Spec.Volumes) + mounts
+			}, timeout, interval).Should(Equal(0))
+		})
+	})
+})
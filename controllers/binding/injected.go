@@ -0,0 +1,244 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// InjectedAnnotationPrefix prefixes the application annotation recording the
+// volumes and environment variables injected for a ServiceBinding, followed by
+// the name of the ServiceBinding
+const InjectedAnnotationPrefix = "binding.kubepreset.dev/injected-"
+
+// injectedEntries are the volumes and environment variables injected into an
+// application for a ServiceBinding.  The environment variables are listed by
+// container name, or by path for the env lists of a mapping.
+type injectedEntries struct {
+	Volumes []string            `json:"volumes,omitempty"`
+	Env     map[string][]string `json:"env,omitempty"`
+}
+
+// injectedAnnotation returns the key of the application annotation recording
+// the entries injected for the ServiceBinding
+func injectedAnnotation(sb *bindingv1beta1.ServiceBinding) string {
+	name := sb.Name
+	if max := 63 - len("injected-"); len(name) > max {
+		// the name part of an annotation key must end with an alphanumeric character
+		name = strings.TrimRight(name[:max], "-.")
+	}
+	return InjectedAnnotationPrefix + name
+}
+
+// injectedEntriesOf returns the entries recorded for the ServiceBinding on the
+// application, nil for an application bound before they were recorded
+func injectedEntriesOf(application *unstructured.Unstructured, sb *bindingv1beta1.ServiceBinding) *injectedEntries {
+	value, ok := application.GetAnnotations()[injectedAnnotation(sb)]
+	if !ok {
+		return nil
+	}
+	injected := &injectedEntries{}
+	if err := json.Unmarshal([]byte(value), injected); err != nil {
+		// an unreadable record is handled as a missing one
+		return nil
+	}
+	return injected
+}
+
+// setInjectedEntries records the entries injected for the ServiceBinding on
+// the application
+func setInjectedEntries(application *unstructured.Unstructured, sb *bindingv1beta1.ServiceBinding,
+	injected *injectedEntries) error {
+
+	value, err := json.Marshal(injected)
+	if err != nil {
+		return err
+	}
+	annotations := application.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[injectedAnnotation(sb)] = string(value)
+	application.SetAnnotations(annotations)
+	return nil
+}
+
+// removeInjectedEntries removes the record of the entries injected for the
+// ServiceBinding from the application
+func removeInjectedEntries(application *unstructured.Unstructured, sb *bindingv1beta1.ServiceBinding) {
+	annotations := application.GetAnnotations()
+	if _, ok := annotations[injectedAnnotation(sb)]; !ok {
+		return
+	}
+	delete(annotations, injectedAnnotation(sb))
+	application.SetAnnotations(annotations)
+}
+
+// rootInjectedByOthers reports whether another ServiceBinding recorded the
+// SERVICE_BINDING_ROOT variable of the container, or env list, as injected
+func rootInjectedByOthers(application *unstructured.Unstructured, sb *bindingv1beta1.ServiceBinding, key string) bool {
+	for annotation, value := range application.GetAnnotations() {
+		if !strings.HasPrefix(annotation, InjectedAnnotationPrefix) || annotation == injectedAnnotation(sb) {
+			continue
+		}
+		other := &injectedEntries{}
+		if err := json.Unmarshal([]byte(value), other); err != nil {
+			continue
+		}
+		if containsString(other.Env[key], ServiceBindingRoot) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedVolumes returns a matcher of the names of the volumes, and volume
+// mounts, injected for the ServiceBinding: the bound volumes and the volumes
+// recorded on the application.  The volumes of an application bound before
+// they were recorded are matched by the shape of their names.
+func ownedVolumes(sb *bindingv1beta1.ServiceBinding, injected *injectedEntries, bound []boundVolume) func(string) bool {
+	names := map[string]bool{}
+	for _, bv := range bound {
+		names[bv.name] = true
+	}
+	if injected != nil {
+		for _, name := range injected.Volumes {
+			names[name] = true
+		}
+	}
+	return func(name string) bool {
+		return names[name] || (injected == nil && legacyVolumeName(sb, name))
+	}
+}
+
+// legacyVolumeName reports whether the volume is named as the volumes
+// projected for the ServiceBinding: the prefix of a single service followed by
+// a volume suffix, or the prefix of selected services followed by the digest
+// of the service and a volume suffix
+func legacyVolumeName(sb *bindingv1beta1.ServiceBinding, name string) bool {
+	if rest := strings.TrimPrefix(name, volumeNamePrefix(sb, 56)); rest != name && isVolumeSuffix(rest) {
+		return true
+	}
+	rest := strings.TrimPrefix(name, volumeNamePrefix(sb, 40))
+	if rest == name || len(rest) < 10 || rest[8] != '-' {
+		return false
+	}
+	return strings.Trim(rest[:8], "0123456789abcdef") == "" && isVolumeSuffix(rest[9:])
+}
+
+// isVolumeSuffix reports whether s is a resource version or the stable suffix
+// ending the names of the projected volumes
+func isVolumeSuffix(s string) bool {
+	if s == stableVolumeSuffix {
+		return true
+	}
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// injectedEnv returns a matcher of the environment variables of the container,
+// or env list, injected for the ServiceBinding.  SERVICE_BINDING_ROOT is kept
+// while another ServiceBinding relies on it.  The variables of an application
+// bound before they were recorded are matched by the env of the ServiceBinding.
+func injectedEnv(application *unstructured.Unstructured, sb *bindingv1beta1.ServiceBinding,
+	injected *injectedEntries, key string) func(string) bool {
+
+	if injected == nil {
+		names := make([]string, 0, len(sb.Spec.Env))
+		for _, e := range sb.Spec.Env {
+			names = append(names, e.Name)
+		}
+		return func(name string) bool {
+			return containsString(names, name)
+		}
+	}
+	names := injected.Env[key]
+	shared := rootInjectedByOthers(application, sb, key)
+	return func(name string) bool {
+		if name == ServiceBindingRoot && shared {
+			return false
+		}
+		return containsString(names, name)
+	}
+}
+
+// injectedEnvNames returns the names of the environment variables of the
+// container, or env list, injected for the ServiceBinding.  SERVICE_BINDING_ROOT
+// is recorded by the ServiceBinding adding it and by the ServiceBindings
+// sharing it, so that the last one unbound removes it.
+func injectedEnvNames(application *unstructured.Unstructured, sb *bindingv1beta1.ServiceBinding,
+	injected *injectedEntries, key string, rootAdded bool) []string {
+
+	names := make([]string, 0, len(sb.Spec.Env)+1)
+	for _, e := range sb.Spec.Env {
+		names = append(names, e.Name)
+	}
+	if rootAdded || (injected != nil && containsString(injected.Env[key], ServiceBindingRoot)) ||
+		rootInjectedByOthers(application, sb, key) {
+		names = append(names, ServiceBindingRoot)
+	}
+	return names
+}
+
+// setBindingEnv sets the environment variables of the ServiceBinding from the
+// binding data, replacing the variables of the same name in place, and removes
+// the variables a previous reconciliation injected that the ServiceBinding no
+// longer declares
+func setBindingEnv(env []corev1.EnvVar, sb *bindingv1beta1.ServiceBinding, data map[string][]byte,
+	previous []string) []corev1.EnvVar {
+
+	values := map[string]string{}
+	for _, e := range sb.Spec.Env {
+		values[e.Name] = string(data[e.Key])
+	}
+	set := map[string]bool{}
+	updated := make([]corev1.EnvVar, 0, len(env)+len(sb.Spec.Env))
+	for _, e := range env {
+		if value, ok := values[e.Name]; ok {
+			if !set[e.Name] {
+				updated = append(updated, corev1.EnvVar{Name: e.Name, Value: value})
+				set[e.Name] = true
+			}
+			continue
+		}
+		if e.Name != ServiceBindingRoot && containsString(previous, e.Name) {
+			continue
+		}
+		updated = append(updated, e)
+	}
+	for _, e := range sb.Spec.Env {
+		if !set[e.Name] {
+			updated = append(updated, corev1.EnvVar{Name: e.Name, Value: values[e.Name]})
+			set[e.Name] = true
+		}
+	}
+	return updated
+}
+
+// previousEnv returns the environment variables of the container, or env list,
+// recorded as injected for the ServiceBinding
+func previousEnv(injected *injectedEntries, key string) []string {
+	if injected == nil {
+		return nil
+	}
+	return injected.Env[key]
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// DefaultClusterDomain is the DNS domain of the cluster used for the `host`
+// entry derived from a `v1/Service`
+const DefaultClusterDomain = "cluster.local"
+
+// ServicePortNotFoundErr represents the error when the port of a `v1/Service` cannot be found
+type ServicePortNotFoundErr struct {
+	Service string
+	Port    string
+}
+
+func (e ServicePortNotFoundErr) Error() string {
+	if e.Port == "" {
+		return fmt.Sprintf("Service %q has no ports", e.Service)
+	}
+	return fmt.Sprintf("Service %q has no port %q", e.Service, e.Port)
+}
+
+// isKubeService reports whether the ServiceBinding refers to a `v1/Service`
+func isKubeService(sb *bindingv1beta1.ServiceBinding) bool {
+	return sb.Spec.Service.Kind == "Service" && sb.Spec.Service.APIVersion == "v1"
+}
+
+// kubeServiceBindingData derives the `host`, `port` and `uri` entries from the
+// `v1/Service` referred by the ServiceBinding and merges them with the entries
+// of the credentials Secret
+func (r *ServiceBindingReconciler) kubeServiceBindingData(ctx context.Context,
+	sb *bindingv1beta1.ServiceBinding) (map[string][]byte, error) {
+
+	svc := &corev1.Service{}
+	key := client.ObjectKey{Name: sb.Spec.Service.Name, Namespace: serviceNamespace(sb)}
+	if err := r.APIReader.Get(ctx, key, svc); err != nil {
+		return nil, err
+	}
+
+	port, err := servicePort(svc, sb.Spec.Service.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{}
+	if ref := sb.Spec.Service.Credentials; ref != nil {
+		secret := &corev1.Secret{}
+		if err := r.APIReader.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: svc.Namespace}, secret); err != nil {
+			return nil, err
+		}
+		for k, v := range secret.Data {
+			data[k] = v
+		}
+	}
+
+	clusterDomain := r.ClusterDomain
+	if clusterDomain == "" {
+		clusterDomain = DefaultClusterDomain
+	}
+	host := fmt.Sprintf("%s.%s.svc.%s", svc.Name, svc.Namespace, clusterDomain)
+	data["host"] = []byte(host)
+	data["port"] = []byte(strconv.Itoa(int(port)))
+	if sb.Spec.Service.Scheme != "" {
+		data["uri"] = []byte(fmt.Sprintf("%s://%s:%d", sb.Spec.Service.Scheme, host, port))
+	}
+	return data, nil
+}
+
+// servicePort returns the port of the Service with the given name or number,
+// or the first port when none is given
+func servicePort(svc *corev1.Service, port string) (int32, error) {
+	if len(svc.Spec.Ports) == 0 {
+		return 0, ServicePortNotFoundErr{Service: svc.Name}
+	}
+	if port == "" {
+		return svc.Spec.Ports[0].Port, nil
+	}
+	for _, p := range svc.Spec.Ports {
+		if p.Name == port || strconv.Itoa(int(p.Port)) == port {
+			return p.Port, nil
+		}
+	}
+	return 0, ServicePortNotFoundErr{Service: svc.Name, Port: port}
+}
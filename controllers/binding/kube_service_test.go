@@ -0,0 +1,254 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Kubernetes Service:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the service is a `v1/Service` with a credentials Secret", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb15",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb15", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			for _, obj := range []client.Object{
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc15", Namespace: testNamespace}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret15", Namespace: testNamespace}},
+			} {
+				err := k8sClient.Delete(ctx, obj, client.GracePeriodSeconds(0))
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should derive the host, port and uri entries from the Service", func() {
+			ctx := context.Background()
+
+			By("Creating Service")
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc15",
+					Namespace: testNamespace,
+				},
+				Spec: corev1.ServiceSpec{
+					Selector: map[string]string{"environment": "test15"},
+					Ports: []corev1.ServicePort{
+						{Name: "metrics", Port: 9187},
+						{Name: "postgresql", Port: 5432},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, svc)).Should(Succeed())
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret15",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"username": "guest",
+					"password": "password",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb15",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Type: "postgresql",
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app15",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion:  "v1",
+						Kind:        "Service",
+						Name:        "svc15",
+						Port:        "postgresql",
+						Scheme:      "postgresql",
+						Credentials: &corev1.LocalObjectReference{Name: "secret15"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			bindingSecretLookupKey := types.NamespacedName{Name: "sb15-binding", Namespace: testNamespace}
+			bindingSecret := &corev1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, bindingSecretLookupKey, bindingSecret)
+			}, timeout, interval).Should(Succeed())
+
+			Expect(string(bindingSecret.Data["type"])).To(Equal("postgresql"))
+			Expect(string(bindingSecret.Data["host"])).To(Equal("svc15.default.svc.cluster.local"))
+			Expect(string(bindingSecret.Data["port"])).To(Equal("5432"))
+			Expect(string(bindingSecret.Data["uri"])).To(Equal("postgresql://svc15.default.svc.cluster.local:5432"))
+			Expect(string(bindingSecret.Data["username"])).To(Equal("guest"))
+		})
+	})
+	Context("When the `v1/Service` is in another namespace", func() {
+
+		const serviceNamespace = "test15-data"
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb15x",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb15x", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: serviceNamespace}}
+			err = k8sClient.Delete(ctx, ns, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not bind a credentials Secret the BindingGrant does not cover", func() {
+			ctx := context.Background()
+
+			By("Creating the service namespace, Service and Secret")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: serviceNamespace}}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc15x",
+					Namespace: serviceNamespace,
+				},
+				Spec: corev1.ServiceSpec{
+					Selector: map[string]string{"environment": "test15x"},
+					Ports:    []corev1.ServicePort{{Name: "postgresql", Port: 5432}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, svc)).Should(Succeed())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret15x",
+					Namespace: serviceNamespace,
+				},
+				StringData: map[string]string{
+					"username": "admin",
+					"password": "password",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Granting only the Service")
+			grant := &bindingv1beta1.BindingGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "grant15x",
+					Namespace: serviceNamespace,
+				},
+				Spec: bindingv1beta1.BindingGrantSpec{
+					From: []bindingv1beta1.BindingGrantFrom{{Namespace: testNamespace}},
+					To:   []bindingv1beta1.BindingGrantTo{{Kind: "Service", Name: "svc15x"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, grant)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb15x",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app15x",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion:  "v1",
+						Kind:        "Service",
+						Name:        "svc15x",
+						Namespace:   serviceNamespace,
+						Credentials: &corev1.LocalObjectReference{Name: "secret15x"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb15x", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return ""
+				}
+				for _, condition := range createdServiceBinding.Status.Conditions {
+					if condition.Type == bindingv1beta1.ConditionReady &&
+						condition.Status == bindingv1beta1.ConditionFalse {
+						return condition.Reason
+					}
+				}
+				return ""
+			}, timeout, interval).Should(ContainSubstring("Secret " + serviceNamespace + "/secret15x is not granted"))
+
+			bindingSecretLookupKey := types.NamespacedName{Name: "sb15x-binding", Namespace: testNamespace}
+			Expect(k8sClient.Get(ctx, bindingSecretLookupKey, &corev1.Secret{})).ShouldNot(Succeed())
+		})
+	})
+})
@@ -244,7 +244,7 @@ var _ = Describe("Label Selector:", func() {
 			}, podTimeout, podInterval).Should(BeTrue())
 
 			Expect(len(createdServiceBinding.Status.Conditions)).To(Equal(1))
-			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("secret5"))
+			Expect(createdServiceBinding.Status.Binding).To(BeNil())
 
 			applicationLookupKey := types.NamespacedName{Name: sb.Spec.Application.Name, Namespace: testNamespace}
 
@@ -540,7 +540,7 @@ var _ = Describe("Label Selector:", func() {
 			}, podTimeout, podInterval).Should(BeTrue())
 
 			Expect(len(createdServiceBinding.Status.Conditions)).To(Equal(1))
-			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("secret6"))
+			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("sb6-binding"))
 
 			applicationLookupKey := types.NamespacedName{Name: "app6", Namespace: testNamespace}
 
@@ -548,7 +548,7 @@ var _ = Describe("Label Selector:", func() {
 			Expect(len(app.Spec.Template.Spec.Volumes)).To(Equal(1))
 
 			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(HavePrefix("sb6-"))
-			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("secret6"))
+			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("sb6-binding"))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_USERNAME", Value: "guest"}))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_PASSWORD", Value: "password"}))
 			Expect(app.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "SERVICE_BINDING_ROOT", Value: "/bindings"}))
@@ -610,7 +610,7 @@ var _ = Describe("Label Selector:", func() {
 			Expect(len(secondApp.Spec.Template.Spec.Volumes)).To(Equal(1))
 
 			Expect(secondApp.Spec.Template.Spec.Volumes[0].Name).To(HavePrefix("sb6-"))
-			Expect(secondApp.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("secret6"))
+			Expect(secondApp.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("sb6-binding"))
 			Expect(secondApp.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_USERNAME", Value: "guest"}))
 			Expect(secondApp.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_PASSWORD", Value: "password"}))
 			Expect(secondApp.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "SERVICE_BINDING_ROOT", Value: "/bindings"}))
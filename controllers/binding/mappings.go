@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// mappingFuncs is the complete set of functions available to mapping
+// templates in addition to the text/template builtins.  None of them have
+// access to the environment, the file system or the network.
+var mappingFuncs = template.FuncMap{
+	"b64enc": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	"b64dec": func(s string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(s)
+		return string(b), err
+	},
+	"default": func(d, s string) string {
+		if s == "" {
+			return d
+		}
+		return s
+	},
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+}
+
+// MappingErr represents the error when a mapping cannot be parsed or rendered
+type MappingErr struct {
+	Name string
+	Err  error
+}
+
+// Error implements the built-in error interface
+func (err MappingErr) Error() string {
+	return fmt.Sprintf("mapping %q: %v", err.Name, err.Err)
+}
+
+// Unwrap returns the underlying template error
+func (err MappingErr) Unwrap() error {
+	return err.Err
+}
+
+// applyMappings renders the mappings in order and adds the results to data.
+// Every mapping can refer to the entries of data and the mappings before it.
+func applyMappings(mappings []bindingv1beta1.Mapping, data map[string][]byte) error {
+	values := make(map[string]string, len(data)+len(mappings))
+	for k, v := range data {
+		values[k] = string(v)
+	}
+	for _, m := range mappings {
+		if errs := validation.IsConfigMapKey(m.Name); len(errs) > 0 {
+			return MappingErr{Name: m.Name, Err: fmt.Errorf("invalid name: %s", strings.Join(errs, "; "))}
+		}
+		tmpl, err := template.New(m.Name).Funcs(mappingFuncs).Option("missingkey=error").Parse(m.Value)
+		if err != nil {
+			return MappingErr{Name: m.Name, Err: err}
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, values); err != nil {
+			return MappingErr{Name: m.Name, Err: err}
+		}
+		values[m.Name] = buf.String()
+		data[m.Name] = buf.Bytes()
+	}
+	return nil
+}
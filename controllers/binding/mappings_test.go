@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Mappings:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When creating ServiceBinding with mappings", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb9",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb9", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret9",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		createServiceBinding := func(ctx context.Context, mappings ...bindingv1beta1.Mapping) {
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret9",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "postgresql",
+					"host":     "db.example.org",
+					"port":     "5432",
+					"database": "orders",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb9",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app9",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret9",
+					},
+					Mappings: mappings,
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+		}
+
+		It("should render the mappings into the binding Secret", func() {
+			ctx := context.Background()
+
+			createServiceBinding(ctx,
+				bindingv1beta1.Mapping{Name: "url", Value: "jdbc:postgresql://{{ .host }}:{{ .port }}/{{ .database }}"},
+				bindingv1beta1.Mapping{Name: "jdbc-url", Value: "{{ .url | upper }}"},
+			)
+
+			bindingSecretLookupKey := types.NamespacedName{Name: "sb9-binding", Namespace: testNamespace}
+			bindingSecret := &corev1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, bindingSecretLookupKey, bindingSecret)
+			}, timeout, interval).Should(Succeed())
+
+			Expect(string(bindingSecret.Data["url"])).To(Equal("jdbc:postgresql://db.example.org:5432/orders"))
+			Expect(string(bindingSecret.Data["jdbc-url"])).To(Equal("JDBC:POSTGRESQL://DB.EXAMPLE.ORG:5432/ORDERS"))
+			Expect(string(bindingSecret.Data["host"])).To(Equal("db.example.org"))
+		})
+
+		It("should update the ServiceBinding status conditions for type `Ready` with value `False` for an invalid mapping", func() {
+			ctx := context.Background()
+
+			createServiceBinding(ctx,
+				bindingv1beta1.Mapping{Name: "url", Value: "{{ .username }}@{{ .host }}"},
+			)
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb9", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding)
+				if err != nil {
+					return false
+				}
+				for _, condition := range createdServiceBinding.Status.Conditions {
+					if condition.Type == bindingv1beta1.ConditionReady &&
+						condition.Status == bindingv1beta1.ConditionFalse {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(createdServiceBinding.Status.Conditions[0].Reason).To(ContainSubstring(`mapping "url"`))
+		})
+
+		It("should update the ServiceBinding status conditions for type `Ready` with value `False` for an invalid mapping name", func() {
+			ctx := context.Background()
+
+			createServiceBinding(ctx,
+				bindingv1beta1.Mapping{Name: "jdbc/url", Value: "{{ .host }}"},
+			)
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb9", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return ""
+				}
+				for _, condition := range createdServiceBinding.Status.Conditions {
+					if condition.Type == bindingv1beta1.ConditionReady &&
+						condition.Status == bindingv1beta1.ConditionFalse {
+						return condition.Reason
+					}
+				}
+				return ""
+			}, timeout, interval).Should(ContainSubstring(`mapping "jdbc/url": invalid name`))
+		})
+	})
+})
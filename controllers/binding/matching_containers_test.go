@@ -262,14 +262,14 @@ var _ = Describe("Matching Containers:", func() {
 			}, podTimeout, podInterval).Should(BeTrue())
 
 			Expect(len(createdServiceBinding.Status.Conditions)).To(Equal(1))
-			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("secret3"))
+			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("sb3-binding"))
 
 			applicationLookupKey := types.NamespacedName{Name: sb.Spec.Application.Name, Namespace: testNamespace}
 
 			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
 			Expect(len(app.Spec.Template.Spec.Volumes)).To(Equal(1))
 			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(HavePrefix("sb3-"))
-			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("secret3"))
+			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("sb3-binding"))
 
 			Expect(app.Spec.Template.Spec.InitContainers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_USERNAME", Value: "guest"}))
 			Expect(app.Spec.Template.Spec.InitContainers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_PASSWORD", Value: "password"}))
@@ -634,14 +634,14 @@ var _ = Describe("Matching Containers:", func() {
 			}, podTimeout, podInterval).Should(BeTrue())
 
 			Expect(len(createdServiceBinding.Status.Conditions)).To(Equal(1))
-			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("secret4"))
+			Expect(createdServiceBinding.Status.Binding.Name).To(Equal("sb4-binding"))
 
 			applicationLookupKey := types.NamespacedName{Name: sb.Spec.Application.Name, Namespace: testNamespace}
 
 			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
 			Expect(len(app.Spec.Template.Spec.Volumes)).To(Equal(1))
 			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(HavePrefix("sb4-"))
-			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("secret4"))
+			Expect(app.Spec.Template.Spec.Volumes[0].VolumeSource.Projected.Sources[0].Secret.Name).To(Equal("sb4-binding"))
 
 			Expect(app.Spec.Template.Spec.InitContainers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_USERNAME", Value: "guest"}))
 			Expect(app.Spec.Template.Spec.InitContainers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "BACKING_SERVICE_PASSWORD", Value: "password"}))
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Resource Ownership:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When a Secret with the name of the binding Secret already exists", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb8",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb8", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			for _, obj := range []client.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sb8-binding", Namespace: testNamespace}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret8", Namespace: testNamespace}},
+			} {
+				err := k8sClient.Delete(ctx, obj, client.GracePeriodSeconds(0))
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should not modify the Secret and update the ServiceBinding status conditions for type `Ready` with value `False`", func() {
+			ctx := context.Background()
+
+			By("Creating a Secret not owned by the ServiceBinding")
+			userSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb8-binding",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"owner": "user",
+				},
+			}
+			Expect(k8sClient.Create(ctx, userSecret)).Should(Succeed())
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret8",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb8",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app8",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret8",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb8", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding)
+				if err != nil {
+					return false
+				}
+				for _, condition := range createdServiceBinding.Status.Conditions {
+					if condition.Type == bindingv1beta1.ConditionReady &&
+						condition.Status == bindingv1beta1.ConditionFalse {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			userSecretLookupKey := types.NamespacedName{Name: "sb8-binding", Namespace: testNamespace}
+			Consistently(func() bool {
+				existing := &corev1.Secret{}
+				if err := k8sClient.Get(ctx, userSecretLookupKey, existing); err != nil {
+					return false
+				}
+				return string(existing.Data["owner"]) == "user" && len(existing.OwnerReferences) == 0
+			}, time.Second*5, interval).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,340 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// PodBindingWebhookPath is the path the Pod mutating webhook is served at
+const PodBindingWebhookPath = "/mutate-v1-pod"
+
+// maxOwnerDepth bounds the owner chain walked up from a Pod, for example
+// Pod, ReplicaSet, Deployment
+const maxOwnerDepth = 4
+
+// +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod.binding.x-k8s.io,admissionReviewVersions={v1,v1beta1}
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get
+
+// PodBinder injects the ServiceBindings in Pod mode into the Pods of their
+// applications as they are created, leaving the applications untouched.  The
+// bindings are read from the binding Secrets maintained by the
+// ServiceBindingReconciler.
+type PodBinder struct {
+	Client    client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+	AuditOnly bool
+	decoder   *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &PodBinder{}
+
+// InjectDecoder implements admission.DecoderInjector
+func (b *PodBinder) InjectDecoder(d *admission.Decoder) error {
+	b.decoder = d
+	return nil
+}
+
+// podOwner is an object in the owner chain of a Pod.  The labels are nil
+// when the object could not be retrieved.
+type podOwner struct {
+	gvk    schema.GroupVersionKind
+	name   string
+	labels map[string]string
+}
+
+// bindingMode returns the binding mode of the ServiceBinding
+func bindingMode(sb *bindingv1beta1.ServiceBinding) bindingv1beta1.BindingMode {
+	if sb.Spec.Mode == "" {
+		return bindingv1beta1.BindingModeWorkload
+	}
+	return sb.Spec.Mode
+}
+
+// Handle injects the matching ServiceBindings into the Pod
+func (b *PodBinder) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if b.AuditOnly {
+		return admission.Allowed("the controller runs in audit-only mode")
+	}
+
+	pod := &corev1.Pod{}
+	if err := b.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// the name and namespace of a Pod may not be set yet on creation
+	namespace := req.Namespace
+	log := b.Log.WithValues("namespace", namespace, "pod", pod.Name, "generateName", pod.GenerateName)
+
+	serviceBindings := &bindingv1beta1.ServiceBindingList{}
+	if err := b.Client.List(ctx, serviceBindings, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "unable to list the ServiceBindings")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	var owners []podOwner
+	ownersResolved := false
+	updated := pod.DeepCopy()
+	bound := []string{}
+	for i := range serviceBindings.Items {
+		sb := &serviceBindings.Items[i]
+		if bindingMode(sb) != bindingv1beta1.BindingModePod || !sb.DeletionTimestamp.IsZero() ||
+			sb.Spec.Suspend || sb.Spec.DryRun || sb.Spec.Application == nil {
+			continue
+		}
+		if !ownersResolved {
+			owners = b.ownerChain(ctx, log, namespace, pod)
+			ownersResolved = true
+		}
+		matches, err := podMatches(sb.Spec.Application, pod.Labels, owners)
+		if err != nil {
+			log.Error(err, "unable to match the application of the ServiceBinding", "ServiceBinding", sb.Name)
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		volumes, data, err := b.boundVolumes(ctx, sb)
+		if err != nil {
+			log.Error(err, "unable to retrieve the binding Secrets of the ServiceBinding", "ServiceBinding", sb.Name)
+			continue
+		}
+		if len(volumes) == 0 {
+			log.V(1).Info("ServiceBinding has no binding Secret yet", "ServiceBinding", sb.Name)
+			continue
+		}
+		if err := bindPodSpec(&updated.Spec, sb, volumes, data); err != nil {
+			log.Error(err, "unable to inject the ServiceBinding", "ServiceBinding", sb.Name)
+			continue
+		}
+		bound = append(bound, sb.Name)
+	}
+	if len(bound) == 0 {
+		return admission.Allowed("no ServiceBinding in Pod mode matches the Pod")
+	}
+
+	log.V(0).Info("injecting ServiceBindings into the Pod", "ServiceBindings", bound)
+	marshaled, err := json.Marshal(updated)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// ownerChain returns the controllers of the Pod, its controller's controller
+// and so on.  The chain ends at the first owner that cannot be retrieved.
+func (b *PodBinder) ownerChain(ctx context.Context, log logr.Logger, namespace string, pod *corev1.Pod) []podOwner {
+	owners := []podOwner{}
+	var current metav1.Object = pod
+	for len(owners) < maxOwnerDepth {
+		ref := metav1.GetControllerOf(current)
+		if ref == nil {
+			break
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			break
+		}
+		owner := &unstructured.Unstructured{}
+		owner.SetGroupVersionKind(gv.WithKind(ref.Kind))
+		if err := b.APIReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, owner); err != nil {
+			log.V(1).Info("unable to retrieve the owner of the Pod", "owner", ref.Kind+"/"+ref.Name, "error", err.Error())
+			owners = append(owners, podOwner{gvk: gv.WithKind(ref.Kind), name: ref.Name})
+			break
+		}
+		owners = append(owners, podOwner{gvk: gv.WithKind(ref.Kind), name: ref.Name, labels: owner.GetLabels()})
+		current = owner
+	}
+	return owners
+}
+
+// podMatches reports whether the Pod belongs to the application, either
+// because an owner of the Pod is the application or because the Pod or one of
+// its owners of the application kinds matches the application selector
+func podMatches(app *bindingv1beta1.Application, podLabels map[string]string, owners []podOwner) (bool, error) {
+	var selector labels.Selector
+	if app.Selector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(app.Selector)
+		if err != nil {
+			return false, err
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			return true, nil
+		}
+	}
+	for _, owner := range owners {
+		for _, gvk := range applicationKinds(app) {
+			if owner.gvk.GroupKind() != gvk.GroupKind() {
+				continue
+			}
+			if app.Name != "" && owner.name == app.Name {
+				return true, nil
+			}
+			if selector != nil && owner.labels != nil && selector.Matches(labels.Set(owner.labels)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// boundVolumes returns the projected volumes of the binding Secrets of the
+// ServiceBinding, named as the ServiceBindingReconciler names them, and the
+// entries the environment variables are set from
+func (b *PodBinder) boundVolumes(ctx context.Context,
+	sb *bindingv1beta1.ServiceBinding) ([]boundVolume, map[string][]byte, error) {
+
+	if sb.Spec.Service != nil && sb.Spec.Service.Selector != nil {
+		prefix := volumeNamePrefix(sb, 40)
+		secrets := &corev1.SecretList{}
+		if err := b.Client.List(ctx, secrets, client.InNamespace(sb.Namespace), client.HasLabels{BindingSecretLabel}); err != nil {
+			return nil, nil, err
+		}
+		sort.Slice(secrets.Items, func(i, j int) bool {
+			return secrets.Items[i].Annotations[SourceSecretAnnotation] < secrets.Items[j].Annotations[SourceSecretAnnotation]
+		})
+		bound := []boundVolume{}
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			if !metav1.IsControlledBy(secret, sb) {
+				continue
+			}
+			source := secret.Annotations[SourceSecretAnnotation]
+			service := source[strings.LastIndex(source, "/")+1:]
+			items, err := projectionItems(sb.Spec.Files, secret.Data)
+			if err != nil {
+				continue
+			}
+			bv, err := newBoundVolume(prefix+serviceHash(service)+"-"+volumeNameSuffix(sb, secret),
+				bindingDirectory(sb)+"-"+service, secret, items)
+			if err != nil {
+				return nil, nil, err
+			}
+			bound = append(bound, bv)
+		}
+		// as for the applications, the environment variables of selected services are not set
+		return bound, nil, nil
+	}
+
+	if sb.Status.Binding == nil || sb.Status.Binding.Name == "" {
+		return nil, nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := b.Client.Get(ctx, client.ObjectKey{Namespace: sb.Namespace, Name: sb.Status.Binding.Name}, secret); err != nil {
+		return nil, nil, client.IgnoreNotFound(err)
+	}
+	items, err := projectionItems(sb.Spec.Files, secret.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	bv, err := newBoundVolume(volumeNamePrefix(sb, 56)+volumeNameSuffix(sb, secret), bindingDirectory(sb), secret, items)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []boundVolume{bv}, secret.Data, nil
+}
+
+// bindPodSpec injects the bound volumes, their mounts and the environment
+// variables of the ServiceBinding into the PodSpec
+func bindPodSpec(spec *corev1.PodSpec, sb *bindingv1beta1.ServiceBinding,
+	bound []boundVolume, data map[string][]byte) error {
+
+	// the pod template records no injected entries, the volumes are matched by name
+	owned := ownedVolumes(sb, nil, bound)
+	volumes := make([]corev1.Volume, 0, len(spec.Volumes)+len(bound))
+	for _, v := range spec.Volumes {
+		if !owned(v.Name) {
+			volumes = append(volumes, v)
+		}
+	}
+	for _, bv := range bound {
+		v := corev1.Volume{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(bv.volume, &v); err != nil {
+			return err
+		}
+		volumes = append(volumes, v)
+	}
+	spec.Volumes = volumes
+
+	for i := range spec.InitContainers {
+		bindContainer(&spec.InitContainers[i], sb, owned, bound, data)
+	}
+	for i := range spec.Containers {
+		bindContainer(&spec.Containers[i], sb, owned, bound, data)
+	}
+	return nil
+}
+
+// bindContainer injects the mounts of the bound volumes and the environment
+// variables of the ServiceBinding into a container selected by the application
+func bindContainer(c *corev1.Container, sb *bindingv1beta1.ServiceBinding, owned func(string) bool,
+	bound []boundVolume, data map[string][]byte) {
+
+	if !selectedContainer(sb.Spec.Application.Containers, c.Name) {
+		return
+	}
+	c.Env = setBindingEnv(c.Env, sb, data, nil)
+	root := ""
+	for _, e := range c.Env {
+		if e.Name == ServiceBindingRoot {
+			root = e.Value
+			break
+		}
+	}
+	if root == "" {
+		root = "/bindings"
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  ServiceBindingRoot,
+			Value: root,
+		})
+	}
+	c.VolumeMounts = replaceBindingVolumeMounts(c.VolumeMounts, owned, root, bound)
+}
+
+// selectedContainer reports whether the container is selected by name, all
+// containers are selected when none is named
+func selectedContainer(containers []intstr.IntOrString, name string) bool {
+	if len(containers) == 0 {
+		return true
+	}
+	for _, c := range containers {
+		if c.Type == intstr.String && c.StrVal == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
+)
+
+var _ = Describe("Pod Webhook:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the ServiceBinding is in Pod mode", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb27",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb27", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret27",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should inject the binding into the Pods of the application", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test27",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret27",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb27",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Selector: &metav1.LabelSelector{
+							MatchLabels: matchLabels,
+						},
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret27",
+					},
+					Env: []bindingv1beta1.Environment{{
+						Name: "DB_USERNAME",
+						Key:  "username",
+					}},
+					Mode: bindingv1beta1.BindingModePod,
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb27", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return false
+				}
+				return createdServiceBinding.Status.Mode == bindingv1beta1.BindingModePod &&
+					createdServiceBinding.Status.Binding != nil
+			}, timeout, interval).Should(BeTrue())
+
+			By("Admitting a Pod of the application")
+			pod := &corev1.Pod{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "Pod",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "app27-",
+					Labels:       matchLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "ghcr.io/kubepreset/bindingdata:latest",
+						Name:  "bindingdata",
+					}},
+				},
+			}
+			raw, err := json.Marshal(pod)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			decoder, err := admission.NewDecoder(scheme.Scheme)
+			Expect(err).ShouldNot(HaveOccurred())
+			binder := &bindingcontrollers.PodBinder{
+				Client:    k8sClient,
+				APIReader: k8sClient,
+				Log:       ctrl.Log.WithName("bindingcontrollers.podbinder").WithName("Pod"),
+			}
+			Expect(binder.InjectDecoder(decoder)).Should(Succeed())
+
+			resp := binder.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: testNamespace,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(resp.Allowed).To(BeTrue())
+
+			paths := []string{}
+			for _, p := range resp.Patches {
+				paths = append(paths, p.Path)
+			}
+			Expect(paths).To(ContainElement("/spec/volumes"))
+			Expect(paths).To(ContainElement("/spec/containers/0/volumeMounts"))
+			Expect(paths).To(ContainElement("/spec/containers/0/env"))
+
+			By("Admitting a Pod of another application")
+			pod.Labels = map[string]string{"environment": "other27"}
+			raw, err = json.Marshal(pod)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			resp = binder.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: testNamespace,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(BeEmpty())
+		})
+	})
+})
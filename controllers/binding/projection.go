@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// FileNameErr represents the error when the file name an entry is
+// projected as is invalid or used by more than one entry
+type FileNameErr struct {
+	Path    string
+	Entries []string
+}
+
+// Error implements the built-in error interface
+func (err FileNameErr) Error() string {
+	if len(err.Entries) > 1 {
+		return fmt.Sprintf("file %q is projected from more than one entry: %v", err.Path, err.Entries)
+	}
+	return fmt.Sprintf("invalid file name %q for entry %v", err.Path, err.Entries)
+}
+
+// projectionItems returns the items of the binding Secret projection for the
+// include, exclude and rename rules.  It returns nil, projecting every entry
+// under its own name, when no rules are given.
+func projectionItems(files *bindingv1beta1.Files, data map[string][]byte) ([]corev1.KeyToPath, error) {
+	if files == nil || (len(files.Include) == 0 && len(files.Exclude) == 0 && len(files.Rename) == 0) {
+		return nil, nil
+	}
+
+	renames := make(map[string]string, len(files.Rename))
+	for _, rn := range files.Rename {
+		renames[rn.From] = rn.To
+	}
+
+	keys := sortedKeys(data)
+
+	items := []corev1.KeyToPath{}
+	paths := map[string]string{}
+	for _, k := range keys {
+		required := k == "type" || k == "provider"
+		if !required {
+			if len(files.Include) > 0 && !containsString(files.Include, k) {
+				continue
+			}
+			if containsString(files.Exclude, k) {
+				continue
+			}
+		}
+		p := k
+		if to, ok := renames[k]; ok && !required {
+			p = to
+		}
+		if !validFilePath(p) {
+			return nil, FileNameErr{Path: p, Entries: []string{k}}
+		}
+		if other, ok := paths[p]; ok {
+			return nil, FileNameErr{Path: p, Entries: []string{other, k}}
+		}
+		paths[p] = k
+		items = append(items, corev1.KeyToPath{Key: k, Path: p})
+	}
+	return items, nil
+}
+
+// validFilePath reports whether the API server accepts the path of an item of
+// a projection: relative, not starting with `..` and without `..` elements
+func validFilePath(p string) bool {
+	if p == "" || path.IsAbs(p) || strings.HasPrefix(p, "..") {
+		return false
+	}
+	for _, element := range strings.Split(p, "/") {
+		if element == ".." {
+			return false
+		}
+	}
+	return true
+}
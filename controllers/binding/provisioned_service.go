@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// getCRD returns the CustomResourceDefinition of the kind, nil for built-in kinds
+func (r *ServiceBindingReconciler) getCRD(ctx context.Context, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	if gvk.Group == "" {
+		// built-in kinds have no CustomResourceDefinition
+		return nil, nil
+	}
+	rm, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	if err := r.APIReader.Get(ctx, client.ObjectKey{Name: rm.Resource.Resource + "." + gvk.Group}, crd); err != nil {
+		return nil, err
+	}
+	return crd, nil
+}
+
+// canProvideBinding reports whether resources of the kind can implement the
+// provisioned service duck type, that is whether the schema of the kind
+// allows a `status.binding` field.  Kinds served without a
+// CustomResourceDefinition by an aggregated API server are given the benefit
+// of the doubt.
+func (r *ServiceBindingReconciler) canProvideBinding(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	if gvk.Group == "" {
+		return false, nil
+	}
+	crd, err := r.getCRD(ctx, gvk)
+	if err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return true, nil
+		}
+		return false, err
+	}
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return false, err
+	}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok || version["name"] != gvk.Version {
+			continue
+		}
+		status, found, err := unstructured.NestedMap(version, "schema", "openAPIV3Schema", "properties", "status")
+		if err != nil || !found {
+			return false, err
+		}
+		if preserve, _, _ := unstructured.NestedBool(status, "x-kubernetes-preserve-unknown-fields"); preserve {
+			return true, nil
+		}
+		_, found, err = unstructured.NestedMap(status, "properties", "binding")
+		return found, err
+	}
+	return false, fmt.Errorf("version %q of %s is not defined in its CustomResourceDefinition", gvk.Version, gvk.GroupKind())
+}
+
+// watchBackingService starts watching the kind of a backing service so
+// changes to its status trigger the reconciliation of the ServiceBindings
+// referring to it.  The watch is only started once per kind.
+func (r *ServiceBindingReconciler) watchBackingService(gvk schema.GroupVersionKind) error {
+	r.watchesMu.Lock()
+	defer r.watchesMu.Unlock()
+
+	if r.watches[gvk] {
+		return nil
+	}
+	if r.controller == nil {
+		return errors.New("the controller is not set up")
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.controller.Watch(&source.Kind{Type: obj}, handler.EnqueueRequestsFromMapFunc(r.mapBackingServiceToServiceBinding)); err != nil {
+		return err
+	}
+	if r.watches == nil {
+		r.watches = map[schema.GroupVersionKind]bool{}
+	}
+	r.watches[gvk] = true
+	return nil
+}
+
+// mapBackingServiceToServiceBinding enqueues the ServiceBindings referring to the backing service
+func (r *ServiceBindingReconciler) mapBackingServiceToServiceBinding(a client.Object) []reconcile.Request {
+	reply := []reconcile.Request{}
+	serviceBindings := &bindingv1beta1.ServiceBindingList{}
+	if err := r.List(context.Background(), serviceBindings); err != nil {
+		return reply
+	}
+	gvk := a.GetObjectKind().GroupVersionKind()
+	for i := range serviceBindings.Items {
+		sb := &serviceBindings.Items[i]
+		if sb.Spec.Service == nil {
+			continue
+		}
+		if sb.Spec.Service.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(sb.Spec.Service.Selector)
+			if err != nil || !selector.Matches(labels.Set(a.GetLabels())) {
+				continue
+			}
+		} else if sb.Spec.Service.Name != a.GetName() {
+			continue
+		}
+		// cluster-scoped backing services have no namespace
+		if a.GetNamespace() != "" && serviceNamespace(sb) != a.GetNamespace() {
+			continue
+		}
+		if sb.Spec.Service.Kind != gvk.Kind || sb.Spec.Service.APIVersion != gvk.GroupVersion().String() {
+			continue
+		}
+		reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: sb.Namespace,
+			Name:      sb.Name,
+		}})
+	}
+	return reply
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// SnapshotLabel marks the immutable snapshots of the binding data generated
+// for a ServiceBinding
+const SnapshotLabel = "binding.kubepreset.dev/snapshot"
+
+// rollbackEnabled reports whether the binding data of the ServiceBinding is
+// projected from snapshots the applications can be rolled back to
+func rollbackEnabled(sb *bindingv1beta1.ServiceBinding) bool {
+	return sb.Spec.Rollout != nil && sb.Spec.Rollout.Rollback &&
+		bindingMode(sb) == bindingv1beta1.BindingModeWorkload &&
+		(sb.Spec.Service == nil || sb.Spec.Service.Selector == nil)
+}
+
+// snapshotName returns the name of the snapshot of the binding data with the given digest
+func snapshotName(sb *bindingv1beta1.ServiceBinding, hash string) string {
+	suffix := bindingSecretSuffix + "-" + hash
+	name := sb.Name
+	if len(name) > 253-len(suffix) {
+		name = name[:253-len(suffix)]
+	}
+	return name + suffix
+}
+
+// reconcileSnapshot creates the snapshot of the current binding data and
+// returns the snapshot to project.  A new snapshot becomes the current one,
+// unless the binding was rolled back from it.  Only the current, previous and
+// rolled back from snapshots are kept.
+func (r *ServiceBindingReconciler) reconcileSnapshot(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, bindingSecret *corev1.Secret) (*corev1.Secret, error) {
+
+	latest, err := r.createSnapshot(ctx, log, sb, bindingSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if sb.Status.Snapshot == nil {
+		sb.Status.Snapshot = &bindingv1beta1.BindingSnapshot{}
+	}
+	s := sb.Status.Snapshot
+	switch {
+	case s.RolledBackFrom == latest.Name:
+		// the binding data has not changed since the rollback
+	case s.Current == latest.Name:
+		s.RolledBackFrom = ""
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionDegraded)
+	default:
+		log.V(0).Info("binding data changed", "snapshot", latest.Name, "previous", s.Current)
+		s.Previous = s.Current
+		s.Current = latest.Name
+		s.RolledBackFrom = ""
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionDegraded)
+	}
+
+	keep := map[string]bool{s.Current: true, s.Previous: true, s.RolledBackFrom: true}
+	if err := r.pruneSnapshots(ctx, log, sb, keep); err != nil {
+		return nil, err
+	}
+
+	if s.Current == latest.Name {
+		return latest, nil
+	}
+	current := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: sb.Namespace, Name: s.Current}, current); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		// the snapshot rolled back to is gone, project the current binding data
+		log.V(0).Info("snapshot not found, projecting the current binding data", "snapshot", s.Current)
+		s.Current = latest.Name
+		s.Previous = ""
+		s.RolledBackFrom = ""
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionDegraded)
+		return latest, nil
+	}
+	return current, nil
+}
+
+// createSnapshot returns the immutable snapshot of the binding data, and
+// creates it when it does not exist yet
+func (r *ServiceBindingReconciler) createSnapshot(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, bindingSecret *corev1.Secret) (*corev1.Secret, error) {
+
+	name := snapshotName(sb, dataHash(bindingSecret.Data))
+	snapshot := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: sb.Namespace, Name: name}, snapshot)
+	if err == nil {
+		if !metav1.IsControlledBy(snapshot, sb) {
+			return nil, OwnershipConflictErr{Kind: "Secret", Name: name}
+		}
+		return snapshot, nil
+	}
+	if client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+
+	immutable := true
+	snapshot = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: sb.Namespace,
+			Labels: map[string]string{
+				BindableLabel: "true",
+				SnapshotLabel: "true",
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(sb.GetObjectMeta(), sb.GroupVersionKind())},
+		},
+		Immutable: &immutable,
+		Type:      corev1.SecretTypeOpaque,
+		Data:      bindingSecret.Data,
+	}
+	log.V(1).Info("creating snapshot of the binding data", "Secret", name)
+	if err := r.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// pruneSnapshots deletes the snapshots of the binding data generated for the
+// ServiceBinding except the ones named in keep
+func (r *ServiceBindingReconciler) pruneSnapshots(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, keep map[string]bool) error {
+
+	snapshots := &corev1.SecretList{}
+	if err := r.List(ctx, snapshots, client.InNamespace(sb.Namespace), client.HasLabels{SnapshotLabel}); err != nil {
+		return err
+	}
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		if keep[snapshot.Name] || !metav1.IsControlledBy(snapshot, sb) {
+			continue
+		}
+		log.V(1).Info("deleting snapshot of the binding data", "Secret", snapshot.Name)
+		if err := r.Delete(ctx, snapshot); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completeRollout forgets the previous snapshot once the applications rolled
+// out the current one, so later failures of the applications, which are not
+// caused by the binding data change, do not roll the binding back
+func completeRollout(log logr.Logger, sb *bindingv1beta1.ServiceBinding) {
+	s := sb.Status.Snapshot
+	if s == nil || s.Previous == "" {
+		return
+	}
+	log.V(1).Info("the applications rolled out the binding data", "snapshot", s.Current)
+	s.Previous = ""
+}
+
+// rollback re-points the binding at the previous snapshot after the
+// applications failed to roll out, and reports the ServiceBinding as Degraded.
+// It reports whether the binding was rolled back.
+func rollback(log logr.Logger, sb *bindingv1beta1.ServiceBinding) bool {
+	s := sb.Status.Snapshot
+	if s == nil || s.Previous == "" || s.RolledBackFrom != "" {
+		return false
+	}
+
+	failure := ""
+	for _, c := range sb.Status.Conditions {
+		if c.Type == bindingv1beta1.ConditionApplicationRolledOut {
+			failure = c.Message
+		}
+	}
+	log.V(0).Info("rolling back the binding data", "from", s.Current, "to", s.Previous, "failure", failure)
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, bindingv1beta1.Condition{
+		Type:    bindingv1beta1.ConditionDegraded,
+		Status:  bindingv1beta1.ConditionTrue,
+		Reason:  bindingv1beta1.ReasonRolledBack,
+		Message: fmt.Sprintf("rolled back from %s to %s: %s", s.Current, s.Previous, failure),
+	})
+	s.RolledBackFrom = s.Current
+	s.Current = s.Previous
+	s.Previous = ""
+	return true
+}
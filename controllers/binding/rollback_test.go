@@ -0,0 +1,421 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Rollback:", func() {
+
+	const (
+		timeout         = time.Second * 20
+		rollbackTimeout = time.Second * 60
+		interval        = time.Millisecond * 250
+		testNamespace   = "default"
+	)
+
+	Context("When the application fails to roll out after the binding data changes", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb31",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb31", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app31",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret31",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should roll the binding back to the previous snapshot and report Degraded", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test31",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret31",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"username": "guest",
+					"password": "initial",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app31",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb31",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app31",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret31",
+					},
+					Env: []bindingv1beta1.Environment{
+						{Name: "DB_PASSWORD", Key: "password"},
+					},
+					Rollout: &bindingv1beta1.Rollout{
+						ProgressDeadline: &metav1.Duration{Duration: time.Second},
+						Rollback:         true,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb31", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return ""
+				}
+				if createdServiceBinding.Status.Snapshot == nil {
+					return ""
+				}
+				return createdServiceBinding.Status.Snapshot.Current
+			}, timeout, interval).ShouldNot(BeEmpty())
+			initial := createdServiceBinding.Status.Snapshot.Current
+
+			By("Rotating the Secret")
+			// no controller rolls the Deployment out in the test environment
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "secret31", Namespace: testNamespace}, secret); err != nil {
+					return err
+				}
+				secret.Data["password"] = []byte("rotated")
+				return k8sClient.Update(ctx, secret)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return false
+				}
+				for _, c := range createdServiceBinding.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionDegraded && c.Status == bindingv1beta1.ConditionTrue {
+						return true
+					}
+				}
+				return false
+			}, rollbackTimeout, interval).Should(BeTrue())
+
+			Expect(createdServiceBinding.Status.Snapshot.Current).To(Equal(initial))
+			Expect(createdServiceBinding.Status.Snapshot.RolledBackFrom).NotTo(BeEmpty())
+
+			applicationLookupKey := types.NamespacedName{Name: "app31", Namespace: testNamespace}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return ""
+				}
+				for _, v := range app.Spec.Template.Spec.Volumes {
+					if v.Projected != nil && len(v.Projected.Sources) > 0 && v.Projected.Sources[0].Secret != nil {
+						return v.Projected.Sources[0].Secret.Name
+					}
+				}
+				return ""
+			}, timeout, interval).Should(Equal(initial))
+			Expect(app.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "DB_PASSWORD", Value: "initial"}))
+		})
+	})
+	Context("When the application fails after rolling out the changed binding data", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb31b",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb31b", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app31b",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret31b",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not roll the binding back", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test31b",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret31b",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"username": "guest",
+					"password": "initial",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app31b",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb31b",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app31b",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret31b",
+					},
+					Rollout: &bindingv1beta1.Rollout{
+						ProgressDeadline: &metav1.Duration{Duration: time.Minute * 5},
+						Rollback:         true,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb31b", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return ""
+				}
+				if createdServiceBinding.Status.Snapshot == nil {
+					return ""
+				}
+				return createdServiceBinding.Status.Snapshot.Current
+			}, timeout, interval).ShouldNot(BeEmpty())
+			initial := createdServiceBinding.Status.Snapshot.Current
+
+			By("Rotating the Secret")
+			secretLookupKey := types.NamespacedName{Name: "secret31b", Namespace: testNamespace}
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, secretLookupKey, secret); err != nil {
+					return err
+				}
+				secret.Data["password"] = []byte("rotated")
+				return k8sClient.Update(ctx, secret)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return ""
+				}
+				return createdServiceBinding.Status.Snapshot.Previous
+			}, timeout, interval).Should(Equal(initial))
+			rotated := createdServiceBinding.Status.Snapshot.Current
+
+			By("Rolling the Deployment out")
+			// no controller rolls the Deployment out in the test environment
+			applicationLookupKey := types.NamespacedName{Name: "app31b", Namespace: testNamespace}
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return false
+				}
+				app.Status = appsv1.DeploymentStatus{
+					ObservedGeneration: app.Generation,
+					Replicas:           1,
+					UpdatedReplicas:    1,
+					AvailableReplicas:  1,
+				}
+				if err := k8sClient.Status().Update(ctx, app); err != nil {
+					return false
+				}
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return false
+				}
+				for _, c := range createdServiceBinding.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionApplicationRolledOut && c.Status == bindingv1beta1.ConditionTrue {
+						return true
+					}
+				}
+				return false
+			}, rollbackTimeout, interval).Should(BeTrue())
+			Expect(createdServiceBinding.Status.Snapshot.Previous).To(BeEmpty())
+
+			By("Failing the Deployment")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return err
+				}
+				app.Status.Conditions = []appsv1.DeploymentCondition{{
+					Type:   appsv1.DeploymentProgressing,
+					Status: corev1.ConditionFalse,
+					Reason: "ProgressDeadlineExceeded",
+				}}
+				return k8sClient.Status().Update(ctx, app)
+			}, timeout, interval).Should(Succeed())
+
+			// the change of the Secret triggers a reconciliation without changing the binding data
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, secretLookupKey, secret); err != nil {
+					return err
+				}
+				secret.Labels = map[string]string{"test31b": "touched"}
+				return k8sClient.Update(ctx, secret)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return false
+				}
+				for _, c := range createdServiceBinding.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionApplicationRolledOut && c.Status == bindingv1beta1.ConditionFalse {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			Consistently(func() bool {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return false
+				}
+				for _, c := range createdServiceBinding.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionDegraded && c.Status == bindingv1beta1.ConditionTrue {
+						return false
+					}
+				}
+				return createdServiceBinding.Status.Snapshot.Current == rotated
+			}, time.Second*5, interval).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// defaultProgressDeadline is the time the applications have to roll out
+const defaultProgressDeadline = time.Minute * 10
+
+// rolloutPollInterval is the interval the rollout of the applications is
+// checked at while it is in progress
+const rolloutPollInterval = time.Second * 15
+
+// rolloutState is the state of the rollout of an application
+type rolloutState int
+
+const (
+	rolledOut rolloutState = iota
+	rolloutProgressing
+	rolloutFailed
+)
+
+// progressDeadline returns the time the applications have to roll out
+func progressDeadline(sb *bindingv1beta1.ServiceBinding) time.Duration {
+	if sb.Spec.Rollout != nil && sb.Spec.Rollout.ProgressDeadline != nil && sb.Spec.Rollout.ProgressDeadline.Duration > 0 {
+		return sb.Spec.Rollout.ProgressDeadline.Duration
+	}
+	return defaultProgressDeadline
+}
+
+// rolloutStatus returns the state of the rollout of the application, and what
+// it waits for or why it failed.  Deployments, StatefulSets and DaemonSets are
+// checked through their replicas, other kinds through status.observedGeneration
+// and their Ready or Available condition, where available.
+func rolloutStatus(application *unstructured.Unstructured) (rolloutState, string) {
+	obj := application.Object
+	observed, found, _ := unstructured.NestedInt64(obj, "status", "observedGeneration")
+	if found && observed < application.GetGeneration() {
+		return rolloutProgressing, "waiting for the new generation to be observed"
+	}
+
+	gk := application.GroupVersionKind().GroupKind()
+	if gk.Group == "apps" {
+		switch gk.Kind {
+		case "Deployment":
+			if c := statusCondition(obj, "Progressing"); c["status"] == "False" && c["reason"] == "ProgressDeadlineExceeded" {
+				return rolloutFailed, "progress deadline exceeded"
+			}
+			if !found {
+				return rolloutProgressing, "waiting for the new generation to be observed"
+			}
+			replicas := specReplicas(obj)
+			updated, _, _ := unstructured.NestedInt64(obj, "status", "updatedReplicas")
+			total, _, _ := unstructured.NestedInt64(obj, "status", "replicas")
+			available, _, _ := unstructured.NestedInt64(obj, "status", "availableReplicas")
+			if updated < replicas {
+				return rolloutProgressing, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+			}
+			if total > updated {
+				return rolloutProgressing, fmt.Sprintf("%d old replicas pending termination", total-updated)
+			}
+			if available < updated {
+				return rolloutProgressing, fmt.Sprintf("%d of %d updated replicas available", available, updated)
+			}
+			return rolledOut, ""
+		case "StatefulSet":
+			if !found {
+				return rolloutProgressing, "waiting for the new generation to be observed"
+			}
+			if strategy, _, _ := unstructured.NestedString(obj, "spec", "updateStrategy", "type"); strategy == "OnDelete" {
+				return rolledOut, ""
+			}
+			replicas := specReplicas(obj)
+			updated, _, _ := unstructured.NestedInt64(obj, "status", "updatedReplicas")
+			ready, _, _ := unstructured.NestedInt64(obj, "status", "readyReplicas")
+			if updated < replicas {
+				return rolloutProgressing, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+			}
+			if ready < replicas {
+				return rolloutProgressing, fmt.Sprintf("%d of %d replicas ready", ready, replicas)
+			}
+			return rolledOut, ""
+		case "DaemonSet":
+			if !found {
+				return rolloutProgressing, "waiting for the new generation to be observed"
+			}
+			desired, _, _ := unstructured.NestedInt64(obj, "status", "desiredNumberScheduled")
+			updated, _, _ := unstructured.NestedInt64(obj, "status", "updatedNumberScheduled")
+			available, _, _ := unstructured.NestedInt64(obj, "status", "numberAvailable")
+			if updated < desired {
+				return rolloutProgressing, fmt.Sprintf("%d of %d pods updated", updated, desired)
+			}
+			if available < desired {
+				return rolloutProgressing, fmt.Sprintf("%d of %d pods available", available, desired)
+			}
+			return rolledOut, ""
+		}
+	}
+
+	for _, t := range []string{"Ready", "Available"} {
+		if c := statusCondition(obj, t); c["status"] == "False" {
+			message, _ := c["message"].(string)
+			return rolloutProgressing, strings.TrimSpace(fmt.Sprintf("%s condition is False %s", t, message))
+		}
+	}
+	return rolledOut, ""
+}
+
+// statusCondition returns the status condition of the given type, nil when absent
+func statusCondition(obj map[string]interface{}, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conditions {
+		if m, ok := c.(map[string]interface{}); ok && m["type"] == conditionType {
+			return m
+		}
+	}
+	return nil
+}
+
+// specReplicas returns the desired replicas of a workload, 1 when not set
+func specReplicas(obj map[string]interface{}) int64 {
+	replicas, found, _ := unstructured.NestedInt64(obj, "spec", "replicas")
+	if !found {
+		return 1
+	}
+	return replicas
+}
+
+// setRolloutCondition reports the rollout of the applications through the
+// ApplicationRolledOut condition.  Applications that have not rolled out
+// within the progress deadline, counted from the start of the rollout, are
+// reported as failed.  The condition is expected to be removed when the
+// binding changes the applications, which starts a new rollout.  It returns the state of the rollout of all the applications.
+func setRolloutCondition(sb *bindingv1beta1.ServiceBinding, applications []unstructured.Unstructured) rolloutState {
+	failed := []string{}
+	progressing := []string{}
+	for i := range applications {
+		state, message := rolloutStatus(&applications[i])
+		switch state {
+		case rolloutFailed:
+			failed = append(failed, applicationRef(&applications[i])+": "+message)
+		case rolloutProgressing:
+			progressing = append(progressing, applicationRef(&applications[i])+": "+message)
+		}
+	}
+
+	c := bindingv1beta1.Condition{
+		Type:   bindingv1beta1.ConditionApplicationRolledOut,
+		Status: bindingv1beta1.ConditionTrue,
+		Reason: bindingv1beta1.ReasonRolledOut,
+	}
+	state := rolledOut
+	if len(progressing) > 0 {
+		c.Status = bindingv1beta1.ConditionUnknown
+		c.Reason = bindingv1beta1.ReasonRolloutInProgress
+		c.Message = "waiting for " + strings.Join(progressing, "; ")
+		state = rolloutProgressing
+		for _, cond := range sb.Status.Conditions {
+			if cond.Type != bindingv1beta1.ConditionApplicationRolledOut {
+				continue
+			}
+			// a failed rollout stays failed until the applications roll out or change again
+			if cond.Status == bindingv1beta1.ConditionFalse ||
+				(cond.Status == bindingv1beta1.ConditionUnknown && time.Since(cond.LastTransitionTime.Time) > progressDeadline(sb)) {
+				failed = append(failed, progressing...)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		c.Status = bindingv1beta1.ConditionFalse
+		c.Reason = bindingv1beta1.ReasonRolloutFailed
+		c.Message = strings.Join(failed, "; ")
+		state = rolloutFailed
+	}
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, c)
+	return state
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// RotationAnnotationPrefix prefixes the pod template annotation holding the
+// hash of the binding data, followed by the name of the ServiceBinding
+const RotationAnnotationPrefix = "binding.x-k8s.io/hash-"
+
+// stableVolumeSuffix ends the names of the volumes that are not renamed on a
+// change of the binding Secret
+const stableVolumeSuffix = "binding"
+
+// defaultRotationDelay is the delay between two batches of restarts
+const defaultRotationDelay = time.Minute * 1
+
+// rotationStrategy returns the rotation strategy of the ServiceBinding
+func rotationStrategy(sb *bindingv1beta1.ServiceBinding) bindingv1beta1.RotationStrategy {
+	if sb.Spec.Rotation == nil || sb.Spec.Rotation.Strategy == "" {
+		return bindingv1beta1.RotationImmediate
+	}
+	return sb.Spec.Rotation.Strategy
+}
+
+// rotationBatch returns the number of applications restarted at once, 0 for
+// all, and the delay between two batches
+func rotationBatch(sb *bindingv1beta1.ServiceBinding) (int, time.Duration) {
+	if sb.Spec.Rotation == nil {
+		return 0, defaultRotationDelay
+	}
+	delay := defaultRotationDelay
+	if sb.Spec.Rotation.Delay != nil && sb.Spec.Rotation.Delay.Duration > 0 {
+		delay = sb.Spec.Rotation.Delay.Duration
+	}
+	return int(sb.Spec.Rotation.MaxConcurrent), delay
+}
+
+// volumeNameSuffix returns the end of the name of a volume projecting the
+// binding Secret.  With the Immediate strategy the resource version of the
+// Secret renames the volume, and restarts the applications, on every change.
+func volumeNameSuffix(sb *bindingv1beta1.ServiceBinding, secret *corev1.Secret) string {
+	if rotationStrategy(sb) == bindingv1beta1.RotationImmediate {
+		return secret.GetResourceVersion()
+	}
+	return stableVolumeSuffix
+}
+
+// rotationAnnotation returns the key of the pod template annotation holding
+// the hash of the binding data of the ServiceBinding
+func rotationAnnotation(sb *bindingv1beta1.ServiceBinding) string {
+	name := sb.Name
+	if max := 63 - len("hash-"); len(name) > max {
+		// the name part of an annotation key must end with an alphanumeric character
+		name = strings.TrimRight(name[:max], "-.")
+	}
+	return RotationAnnotationPrefix + name
+}
+
+// dataHash returns a short and stable digest of the binding entries
+func dataHash(data map[string][]byte) string {
+	h := fnv.New32a()
+	for _, k := range sortedKeys(data) {
+		_, _ = h.Write([]byte(k))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write(data[k])
+		_, _ = h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// boundDataHash returns a digest of the binding data of all the bound volumes
+func boundDataHash(bound []boundVolume) string {
+	h := fnv.New32a()
+	for _, bv := range bound {
+		_, _ = h.Write([]byte(bv.name + "=" + bv.dataHash + ";"))
+	}
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// setRotationAnnotation sets the hash of the binding data on the pod template
+// of the application with the Annotation strategy, and removes it otherwise.
+// The pod template is located from the path of its volumes.
+func setRotationAnnotation(application *unstructured.Unstructured, volumesPath []string,
+	sb *bindingv1beta1.ServiceBinding, bound []boundVolume) error {
+
+	n := len(volumesPath)
+	if n < 2 || volumesPath[n-2] != "spec" || volumesPath[n-1] != "volumes" {
+		// not the volumes of a pod template
+		return nil
+	}
+	annotationPath := append(append([]string{}, volumesPath[:n-2]...), "metadata", "annotations", rotationAnnotation(sb))
+	if rotationStrategy(sb) != bindingv1beta1.RotationAnnotation {
+		unstructured.RemoveNestedField(application.Object, annotationPath...)
+		return nil
+	}
+	return unstructured.SetNestedField(application.Object, boundDataHash(bound), annotationPath...)
+}
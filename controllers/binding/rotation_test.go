@@ -0,0 +1,272 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
+)
+
+var _ = Describe("Rotation:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	newDeployment := func(name string, matchLabels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Labels:    matchLabels,
+				Namespace: testNamespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: matchLabels,
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: matchLabels,
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Image: "ghcr.io/kubepreset/bindingdata:latest",
+							Name:  "bindingdata",
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	cleanUp := func(sbName, secretName string, appNames ...string) {
+		ctx := context.Background()
+
+		sb := &bindingv1beta1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sbName,
+				Namespace: testNamespace,
+			}}
+		err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		serviceBindingLookupKey := types.NamespacedName{Name: sbName, Namespace: testNamespace}
+		deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+			return err != nil
+		}, timeout, interval).Should(BeTrue())
+
+		for _, name := range appNames {
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: testNamespace,
+			}}
+		err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	Context("When the rotation strategy is Annotation", func() {
+
+		AfterEach(func() {
+			cleanUp("sb28", "secret28", "app28")
+		})
+
+		It("should keep the volume name and change the hash annotation with the data", func() {
+			ctx := context.Background()
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret28",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Deployment")
+			app := newDeployment("app28", map[string]string{"environment": "test28"})
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb28",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app28",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret28",
+					},
+					Rotation: &bindingv1beta1.Rotation{
+						Strategy: bindingv1beta1.RotationAnnotation,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			annotation := bindingcontrollers.RotationAnnotationPrefix + "sb28"
+			applicationLookupKey := types.NamespacedName{Name: "app28", Namespace: testNamespace}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return ""
+				}
+				return app.Spec.Template.Annotations[annotation]
+			}, timeout, interval).ShouldNot(BeEmpty())
+
+			Expect(app.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(Equal("sb28-binding"))
+			hash := app.Spec.Template.Annotations[annotation]
+
+			By("Rotating the Secret")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: "secret28", Namespace: testNamespace}, secret); err != nil {
+					return err
+				}
+				secret.Data["password"] = []byte("rotated")
+				return k8sClient.Update(ctx, secret)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return hash
+				}
+				return app.Spec.Template.Annotations[annotation]
+			}, timeout, interval).ShouldNot(Equal(hash))
+
+			Expect(app.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(Equal("sb28-binding"))
+		})
+	})
+
+	Context("When the applications are restarted in batches", func() {
+
+		AfterEach(func() {
+			cleanUp("sb29", "secret29", "app29a", "app29b")
+		})
+
+		It("should eventually update every application", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test29",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret29",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Deployments")
+			for _, name := range []string{"app29a", "app29b"} {
+				Expect(k8sClient.Create(ctx, newDeployment(name, matchLabels))).Should(Succeed())
+			}
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb29",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Selector: &metav1.LabelSelector{
+							MatchLabels: matchLabels,
+						},
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret29",
+					},
+					Rotation: &bindingv1beta1.Rotation{
+						MaxConcurrent: 1,
+						Delay:         &metav1.Duration{Duration: time.Second * 2},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			for _, name := range []string{"app29a", "app29b"} {
+				applicationLookupKey := types.NamespacedName{Name: name, Namespace: testNamespace}
+				app := &appsv1.Deployment{}
+				Eventually(func() int {
+					if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+						return 0
+					}
+					return len(app.Spec.Template.Spec.Volumes)
+				}, timeout, interval).Should(Equal(1))
+			}
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb29", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() bindingv1beta1.ConditionStatus {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding); err != nil {
+					return ""
+				}
+				for _, c := range createdServiceBinding.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionReady {
+						return c.Status
+					}
+				}
+				return ""
+			}, timeout, interval).Should(Equal(bindingv1beta1.ConditionTrue))
+		})
+	})
+})
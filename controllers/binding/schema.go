@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// TypeSchema describes the entries expected in bindings of a type
+type TypeSchema struct {
+	// Required is the collection of entries a binding of the type must contain
+	Required []string
+}
+
+// EntryValidator validates the value of a well-known entry
+type EntryValidator func(value []byte) error
+
+// WellKnownEntries are the validators for the well-known entries of the spec.
+// Refer: https://github.com/k8s-service-bindings/spec#well-known-secret-entries
+var WellKnownEntries = map[string]EntryValidator{
+	"host":         validateHost,
+	"port":         validatePort,
+	"uri":          validateURI,
+	"username":     validateNotEmpty,
+	"password":     validateNotEmpty,
+	"certificates": validatePEM("CERTIFICATE"),
+	"private-key":  validatePEM("PRIVATE KEY"),
+}
+
+// SchemaRegistry holds the schemas of the binding types.  It is safe for
+// concurrent use.
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]TypeSchema
+}
+
+// NewSchemaRegistry returns a registry with the built-in schemas
+func NewSchemaRegistry() *SchemaRegistry {
+	r := &SchemaRegistry{schemas: map[string]TypeSchema{}}
+	r.Register("postgresql", TypeSchema{Required: []string{"host", "port", "username", "password"}})
+	r.Register("mysql", TypeSchema{Required: []string{"host", "port", "username", "password"}})
+	r.Register("redis", TypeSchema{Required: []string{"host", "port"}})
+	r.Register("rabbitmq", TypeSchema{Required: []string{"host", "port", "username", "password"}})
+	return r
+}
+
+// Register adds or replaces the schema of a binding type
+func (r *SchemaRegistry) Register(bindingType string, schema TypeSchema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[bindingType] = schema
+}
+
+// Lookup returns the schema of a binding type
+func (r *SchemaRegistry) Lookup(bindingType string) (TypeSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.schemas[bindingType]
+	return schema, ok
+}
+
+// Validate returns the problems found in the entries for the schema.
+// Well-known entries are validated whether they are required or not.
+func (s TypeSchema) Validate(data map[string][]byte) []string {
+	problems := []string{}
+	for _, name := range s.Required {
+		if _, ok := data[name]; !ok {
+			problems = append(problems, fmt.Sprintf("missing required entry %q", name))
+		}
+	}
+	for _, k := range sortedKeys(data) {
+		if validate, ok := WellKnownEntries[k]; ok {
+			if err := validate(data[k]); err != nil {
+				problems = append(problems, fmt.Sprintf("invalid entry %q: %v", k, err))
+			}
+		}
+	}
+	return problems
+}
+
+// schemaFor returns the schema of the binding type.  A ClusterBindingType
+// named after the type takes precedence over the registry.
+func (r *ServiceBindingReconciler) schemaFor(ctx context.Context, bindingType string) (TypeSchema, bool, error) {
+	cbt := &bindingv1beta1.ClusterBindingType{}
+	err := r.Get(ctx, client.ObjectKey{Name: bindingType}, cbt)
+	if err == nil {
+		return TypeSchema{Required: cbt.Spec.Required}, true, nil
+	}
+	if client.IgnoreNotFound(err) != nil {
+		return TypeSchema{}, false, err
+	}
+	if r.Schemas == nil {
+		return TypeSchema{}, false, nil
+	}
+	schema, ok := r.Schemas.Lookup(bindingType)
+	return schema, ok, nil
+}
+
+// validateEntries reports the problems in the binding entries through the
+// EntriesValid condition.  The condition is removed for types without a schema.
+func (r *ServiceBindingReconciler) validateEntries(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, data map[string][]byte) {
+
+	bindingType := string(data["type"])
+	schema, found, err := r.schemaFor(ctx, bindingType)
+	if err != nil {
+		log.Error(err, "unable to retrieve the schema of the binding type", "type", bindingType)
+		return
+	}
+	if !found {
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionEntriesValid)
+		return
+	}
+
+	problems := schema.Validate(data)
+	c := bindingv1beta1.Condition{
+		Type:   bindingv1beta1.ConditionEntriesValid,
+		Status: bindingv1beta1.ConditionTrue,
+	}
+	if len(problems) > 0 {
+		log.V(0).Info("binding entries do not match the schema of the type", "type", bindingType, "problems", problems)
+		c.Status = bindingv1beta1.ConditionFalse
+		c.Reason = "entries do not match the schema of the type"
+		c.Message = strings.Join(problems, "; ")
+	}
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, c)
+}
+
+func validateNotEmpty(value []byte) error {
+	if len(strings.TrimSpace(string(value))) == 0 {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+func validateHost(value []byte) error {
+	host := string(value)
+	if err := validateNotEmpty(value); err != nil {
+		return err
+	}
+	if strings.ContainsAny(host, " \t\n/") || strings.Contains(host, "://") {
+		return errors.New("must be a host name or an IP address")
+	}
+	return nil
+}
+
+func validatePort(value []byte) error {
+	port, err := strconv.Atoi(strings.TrimSpace(string(value)))
+	if err != nil || port < 1 || port > 65535 {
+		return errors.New("must be a number between 1 and 65535")
+	}
+	return nil
+}
+
+func validateURI(value []byte) error {
+	u, err := url.Parse(strings.TrimSpace(string(value)))
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" {
+		return errors.New("must be an absolute URI")
+	}
+	return nil
+}
+
+func validatePEM(blockType string) EntryValidator {
+	return func(value []byte) error {
+		rest := value
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				return fmt.Errorf("must contain a PEM encoded %s", blockType)
+			}
+			if strings.HasSuffix(block.Type, blockType) {
+				return nil
+			}
+		}
+	}
+}
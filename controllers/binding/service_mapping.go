@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// ServiceResourceMappingErr represents the error when the entries described by
+// a ClusterServiceResourceMapping cannot be read from the service resource
+type ServiceResourceMappingErr struct {
+	Name string
+	Err  error
+}
+
+func (e ServiceResourceMappingErr) Error() string {
+	return fmt.Sprintf("ClusterServiceResourceMapping %q: %v", e.Name, e.Err)
+}
+
+func (e ServiceResourceMappingErr) Unwrap() error {
+	return e.Err
+}
+
+// serviceResourceBindingData synthesises the binding entries of a backing
+// service that does not implement the provisioned service duck type.  A
+// ClusterServiceResourceMapping for the kind takes precedence over the
+// binding annotations.  It reports false when neither describes the service.
+func (r *ServiceBindingReconciler) serviceResourceBindingData(ctx context.Context, log logr.Logger,
+	backingService *unstructured.Unstructured) (map[string][]byte, bool, error) {
+
+	data, found, err := r.mappingBindingData(ctx, log, backingService)
+	if err != nil || found {
+		return data, found, err
+	}
+	return r.annotationBindingData(ctx, log, backingService)
+}
+
+// mappingBindingData synthesises the binding entries from the
+// ClusterServiceResourceMapping of the kind of the backing service
+func (r *ServiceBindingReconciler) mappingBindingData(ctx context.Context, log logr.Logger,
+	backingService *unstructured.Unstructured) (map[string][]byte, bool, error) {
+
+	gvk := backingService.GroupVersionKind()
+	rm, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, false, err
+	}
+
+	csrm := &bindingv1beta1.ClusterServiceResourceMapping{}
+	csrmLookupKey := client.ObjectKey{Name: rm.Resource.Resource + "." + gvk.Group}
+	if err := r.Get(ctx, csrmLookupKey, csrm); err != nil {
+		log.V(1).Info("unable to retrieve ClusterServiceResourceMapping", "error", err)
+		return nil, false, client.IgnoreNotFound(err)
+	}
+
+	for _, ver := range csrm.Spec.Versions {
+		if ver.Version != gvk.Version && ver.Version != "*" {
+			continue
+		}
+		data, err := r.resolveBindingAnnotations(ctx, backingService, mappingAnnotations(ver))
+		if err != nil {
+			return nil, false, ServiceResourceMappingErr{Name: csrm.Name, Err: err}
+		}
+		if ver.Type != "" {
+			data["type"] = []byte(ver.Type)
+		}
+		if ver.Provider != "" {
+			data["provider"] = []byte(ver.Provider)
+		}
+		log.V(1).Info("binding entries synthesised from the ClusterServiceResourceMapping", "ClusterServiceResourceMapping", csrm.Name)
+		return data, true, nil
+	}
+	return nil, false, nil
+}
+
+// mappingAnnotations expresses a mapping version as binding annotations, so
+// the mapping and the annotations select values the same way
+func mappingAnnotations(ver bindingv1beta1.ClusterServiceResourceMappingVersion) []bindingAnnotation {
+	annotations := []bindingAnnotation{}
+	for _, s := range ver.Secrets {
+		if len(s.Keys) == 0 {
+			annotations = append(annotations, bindingAnnotation{Key: "secrets", Path: s.Path, ObjectType: "Secret"})
+			continue
+		}
+		for _, k := range s.Keys {
+			annotations = append(annotations, bindingAnnotation{Key: "secrets", Path: s.Path, ObjectType: "Secret", SourceKey: k})
+		}
+	}
+	for _, e := range []bindingv1beta1.ClusterServiceResourceMappingEntry{
+		{Name: "host", Path: ver.Host},
+		{Name: "port", Path: ver.Port},
+		{Name: "uri", Path: ver.URI},
+	} {
+		if e.Path != "" {
+			annotations = append(annotations, bindingAnnotation{Key: e.Name, Name: e.Name, Path: e.Path})
+		}
+	}
+	for _, e := range ver.Entries {
+		annotations = append(annotations, bindingAnnotation{Key: "entries", Name: e.Name, Path: e.Path})
+	}
+	return annotations
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Service Resource Mapping:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the backing service does not implement the provisioned service duck type", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb14",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb14", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			backingServiceCRD := &apixv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backingservices.app14.example.org",
+				}}
+			err = k8sClient.Delete(ctx, backingServiceCRD, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			csrm := &bindingv1beta1.ClusterServiceResourceMapping{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backingservices.app14.example.org",
+				}}
+			err = k8sClient.Delete(ctx, csrm, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret14",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should synthesise the binding Secret from the ClusterServiceResourceMapping", func() {
+			ctx := context.Background()
+
+			By("Creating BackingService CRD")
+			preserveUnknownFields := true
+			backingServiceCRD := &apixv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backingservices.app14.example.org",
+				},
+				Spec: apixv1.CustomResourceDefinitionSpec{
+					Group: "app14.example.org",
+					Versions: []apixv1.CustomResourceDefinitionVersion{{
+						Name:    "v1alpha1",
+						Served:  true,
+						Storage: true,
+						Schema: &apixv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apixv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apixv1.JSONSchemaProps{
+									"spec":   {Type: "object", XPreserveUnknownFields: &preserveUnknownFields},
+									"status": {Type: "object", XPreserveUnknownFields: &preserveUnknownFields},
+								},
+							},
+						},
+					}},
+					Names: apixv1.CustomResourceDefinitionNames{
+						Plural: "backingservices",
+						Kind:   "BackingService",
+					},
+					Scope: apixv1.NamespaceScoped,
+				}}
+			Expect(k8sClient.Create(ctx, backingServiceCRD)).Should(Succeed())
+
+			backingServiceCRDLookupKey := types.NamespacedName{Name: "backingservices.app14.example.org"}
+			createdBackingServiceCRD := &apixv1.CustomResourceDefinition{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, backingServiceCRDLookupKey, createdBackingServiceCRD)
+				if err != nil {
+					return false
+				}
+				for _, condition := range createdBackingServiceCRD.Status.Conditions {
+					if condition.Type == apixv1.Established &&
+						condition.Status == apixv1.ConditionTrue {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret14",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"username": "guest",
+					"password": "password",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating ClusterServiceResourceMapping")
+			csrm := &bindingv1beta1.ClusterServiceResourceMapping{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backingservices.app14.example.org",
+				},
+				Spec: bindingv1beta1.ClusterServiceResourceMappingSpec{
+					Versions: []bindingv1beta1.ClusterServiceResourceMappingVersion{{
+						Version: "*",
+						Type:    "postgresql",
+						Host:    "{.status.host}",
+						Port:    "{.spec.port}",
+						Secrets: []bindingv1beta1.ClusterServiceResourceMappingSecret{{
+							Path: "{.status.credentials}",
+							Keys: []string{"username"},
+						}},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, csrm)).Should(Succeed())
+
+			By("Creating BackingService CR")
+			backingServiceCR := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "BackingService",
+					"apiVersion": "app14.example.org/v1alpha1",
+					"metadata": map[string]interface{}{
+						"name":      "back14",
+						"namespace": testNamespace,
+					},
+					"spec": map[string]interface{}{
+						"port": int64(5432),
+					},
+					"status": map[string]interface{}{
+						"host":        "db.example.org",
+						"credentials": "secret14",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, backingServiceCR)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb14",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app14",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "app14.example.org/v1alpha1",
+						Kind:       "BackingService",
+						Name:       "back14",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			bindingSecretLookupKey := types.NamespacedName{Name: "sb14-binding", Namespace: testNamespace}
+			bindingSecret := &corev1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, bindingSecretLookupKey, bindingSecret)
+			}, timeout, interval).Should(Succeed())
+
+			Expect(string(bindingSecret.Data["host"])).To(Equal("db.example.org"))
+			Expect(string(bindingSecret.Data["port"])).To(Equal("5432"))
+			Expect(string(bindingSecret.Data["type"])).To(Equal("postgresql"))
+			Expect(string(bindingSecret.Data["username"])).To(Equal("guest"))
+			Expect(bindingSecret.Data).ShouldNot(HaveKey("password"))
+		})
+	})
+})
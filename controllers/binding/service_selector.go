@@ -0,0 +1,286 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// selectedBindingSecretName returns the name of the binding Secret generated
+// for a backing service matched by the service selector
+func selectedBindingSecretName(sb *bindingv1beta1.ServiceBinding, service string) string {
+	name := sb.Name + "-" + service
+	if len(name) > 253-len(bindingSecretSuffix) {
+		name = name[:253-len(bindingSecretSuffix)]
+	}
+	return name + bindingSecretSuffix
+}
+
+// serviceHash returns a short and stable digest of the name of a backing service
+func serviceHash(service string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(service))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// reconcileSelectedServices binds every backing service matching the service
+// selector.  Each service gets its own binding Secret and volume, mounted at
+// `<name>-<service name>` under $SERVICE_BINDING_ROOT.  Services without
+// binding data are reported through the ServiceAvailable condition and keep
+// their previous binding, only the services no longer matching are unbound.
+func (r *ServiceBindingReconciler) reconcileSelectedServices(ctx context.Context, log logr.Logger, req ctrl.Request,
+	sb bindingv1beta1.ServiceBinding) (ctrl.Result, error) {
+
+	var conditionStatus bindingv1beta1.ConditionStatus = "False"
+	var reason string
+
+	if sb.Spec.Service.Name != "" {
+		reason = "service name and selector cannot be used together"
+		log.Error(errors.New(reason), "invalid service reference")
+		return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+	}
+	if len(sb.Spec.Env) > 0 {
+		reason = "env cannot be used with a service selector"
+		log.Error(errors.New(reason), "invalid service reference")
+		return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sb.Spec.Service.Selector)
+	if err != nil {
+		reason = "invalid service selector: " + err.Error()
+		log.Error(err, "invalid service selector")
+		return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+	}
+
+	gvk := schema.FromAPIVersionAndKind(sb.Spec.Service.APIVersion, sb.Spec.Service.Kind)
+	if gvk.Group != "" {
+		if err := r.watchBackingService(gvk); err != nil {
+			log.Error(err, "unable to watch the backing service kind", "GVK", gvk)
+		}
+	}
+
+	services := &unstructured.UnstructuredList{}
+	services.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.APIReader.List(ctx, services, client.InNamespace(serviceNamespace(&sb)),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		reason = "unable to retrieve the backing services"
+		log.Error(err, reason)
+		return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+	}
+	sort.Slice(services.Items, func(i, j int) bool {
+		return services.Items[i].GetName() < services.Items[j].GetName()
+	})
+
+	// the volume names must fit in 63 characters along with the service
+	// digest and the resource version of the binding Secret
+	prefix := volumeNamePrefix(&sb, 40)
+	keep := map[string]bool{}
+	bound := []boundVolume{}
+	unavailable := []string{}
+	for i := range services.Items {
+		service := &services.Items[i]
+		// a matching service without binding data for now keeps its previous binding
+		keepPrevious := func() error {
+			bv, err := r.previousSelectedBinding(ctx, &sb, service.GetName(), prefix)
+			if err != nil || bv == nil {
+				return err
+			}
+			keep[selectedBindingSecretName(&sb, service.GetName())] = true
+			bound = append(bound, *bv)
+			return nil
+		}
+
+		data, found, err := r.selectedServiceData(ctx, log, service)
+		if err != nil {
+			log.Error(err, "unable to retrieve the binding data of the backing service", "service", service.GetName())
+			unavailable = append(unavailable, fmt.Sprintf("%s: %v", service.GetName(), err))
+			if err := keepPrevious(); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		if !found {
+			unavailable = append(unavailable, service.GetName()+": no binding data")
+			if err := keepPrevious(); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+
+		data = bindingData(&sb, &corev1.Secret{Data: data})
+		if err := r.mergeSources(ctx, log, &sb, data); err != nil {
+			reason = "unable to merge the sources: " + err.Error()
+			log.Error(err, "unable to merge the sources")
+			return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+		}
+		if err := applyMappings(sb.Spec.Mappings, data); err != nil {
+			reason = err.Error()
+			log.Error(err, "unable to render the mappings")
+			return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+		}
+		if _, ok := data["type"]; !ok {
+			unavailable = append(unavailable, service.GetName()+": no `type` entry")
+			if err := keepPrevious(); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+
+		name := selectedBindingSecretName(&sb, service.GetName())
+		source := types.NamespacedName{Namespace: service.GetNamespace(), Name: service.GetName()}
+		secret, err := r.reconcileNamedBindingSecret(ctx, log, &sb, name, source, data)
+		if err != nil {
+			var ownershipErr OwnershipConflictErr
+			if errors.As(err, &ownershipErr) {
+				unavailable = append(unavailable, service.GetName()+": "+err.Error())
+				continue
+			}
+			log.Error(err, "unable to create or update the binding Secret")
+			return ctrl.Result{}, err
+		}
+		keep[name] = true
+
+		items, err := projectionItems(sb.Spec.Files, secret.Data)
+		if err != nil {
+			unavailable = append(unavailable, service.GetName()+": "+err.Error())
+			continue
+		}
+		bv, err := newBoundVolume(prefix+serviceHash(service.GetName())+"-"+volumeNameSuffix(&sb, secret),
+			bindingDirectory(&sb)+"-"+service.GetName(), secret, items)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		bound = append(bound, bv)
+	}
+	if err := r.pruneBindingSecrets(ctx, log, &sb, keep); err != nil {
+		log.Error(err, "unable to delete the binding Secrets of unmatched services")
+		return ctrl.Result{}, err
+	}
+
+	if len(unavailable) > 0 {
+		log.V(0).Info("backing services without binding data", "services", unavailable)
+		sb.Status.Conditions = setCondition(sb.Status.Conditions, bindingv1beta1.Condition{
+			Type:    bindingv1beta1.ConditionServiceAvailable,
+			Status:  bindingv1beta1.ConditionFalse,
+			Reason:  bindingv1beta1.ReasonServiceNotReady,
+			Message: strings.Join(unavailable, "; "),
+		})
+	} else {
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionServiceAvailable)
+	}
+	if len(bound) == 0 {
+		reason = "no backing service matching the selector provides binding data"
+		if _, err := r.setStatus(ctx, log, "", sb, conditionStatus, reason); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute * 1}, nil
+	}
+	r.boundVolumes = bound
+	// env cannot be used with a service selector
+	r.boundData = nil
+
+	known, err := r.checkApplicationKind(log, &sb)
+	if err != nil {
+		log.Error(err, "unable to determine the RESTMapping of the application")
+		return ctrl.Result{}, err
+	}
+	if !known {
+		reason = "the kind of the application is not served by the cluster"
+		return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+	}
+
+	applications, result, err := r.getApplication(ctx, log, req, sb, "")
+	if err != nil || len(applications) == 0 {
+		return result, err
+	}
+	// the status refers to no single binding Secret
+	result, err = r.bindApplications(ctx, log, req, sb, &corev1.Secret{}, applications...)
+	if err == nil && !result.Requeue && result.RequeueAfter == 0 {
+		// services joining or leaving the selection are picked up periodically
+		result.RequeueAfter = time.Minute * 1
+	}
+	return result, err
+}
+
+// previousSelectedBinding returns the volume of the binding Secret generated for
+// a backing service by a previous reconciliation, nil without one
+func (r *ServiceBindingReconciler) previousSelectedBinding(ctx context.Context, sb *bindingv1beta1.ServiceBinding,
+	service, prefix string) (*boundVolume, error) {
+
+	secret := &corev1.Secret{}
+	name := selectedBindingSecretName(sb, service)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: sb.Namespace, Name: name}, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(secret, sb) {
+		return nil, nil
+	}
+	items, err := projectionItems(sb.Spec.Files, secret.Data)
+	if err != nil {
+		return nil, nil
+	}
+	bv, err := newBoundVolume(prefix+serviceHash(service)+"-"+volumeNameSuffix(sb, secret),
+		bindingDirectory(sb)+"-"+service, secret, items)
+	if err != nil {
+		return nil, err
+	}
+	return &bv, nil
+}
+
+// selectedServiceData returns the binding entries of a backing service matched
+// by the service selector
+func (r *ServiceBindingReconciler) selectedServiceData(ctx context.Context, log logr.Logger,
+	service *unstructured.Unstructured) (map[string][]byte, bool, error) {
+
+	if service.GetAPIVersion() == "v1" && service.GetKind() == "Secret" {
+		secret := &corev1.Secret{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(service.Object, secret); err != nil {
+			return nil, false, err
+		}
+		return secret.Data, true, nil
+	}
+
+	name, _, err := unstructured.NestedString(service.Object, "status", "binding", "name")
+	if err != nil {
+		return nil, false, err
+	}
+	if name == "" {
+		return r.serviceResourceBindingData(ctx, log, service)
+	}
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, client.ObjectKey{Namespace: service.GetNamespace(), Name: name}, secret); err != nil {
+		return nil, false, err
+	}
+	return secret.Data, true, nil
+}
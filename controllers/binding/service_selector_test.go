@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Service Selector:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the ServiceBinding selects backing services by label", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb22",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb22", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app22",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, name := range []string{"redis22a", "redis22b"} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespace,
+					}}
+				err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should project one volume per matching service", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test22",
+			}
+
+			By("Creating Secrets")
+			for _, name := range []string{"redis22a", "redis22b"} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespace,
+						Labels:    map[string]string{"tenant-service": "redis22"},
+					},
+					StringData: map[string]string{
+						"type": "redis",
+						"host": name + ".example.org",
+						"port": "6379",
+					},
+				}
+				Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+			}
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app22",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb22",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app22",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"tenant-service": "redis22"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			applicationLookupKey := types.NamespacedName{Name: "app22", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return len(app.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(2))
+
+			mountPaths := []string{}
+			for _, vm := range app.Spec.Template.Spec.Containers[0].VolumeMounts {
+				mountPaths = append(mountPaths, vm.MountPath)
+			}
+			Expect(mountPaths).To(ConsistOf("/bindings/sb22-redis22a", "/bindings/sb22-redis22b"))
+
+			bindingSecret := &corev1.Secret{}
+			bindingSecretLookupKey := types.NamespacedName{Name: "sb22-redis22a-binding", Namespace: testNamespace}
+			Expect(k8sClient.Get(ctx, bindingSecretLookupKey, bindingSecret)).Should(Succeed())
+			Expect(string(bindingSecret.Data["host"])).To(Equal("redis22a.example.org"))
+
+			By("Removing the binding data of a matching service")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "redis22b", Namespace: testNamespace}, secret)).Should(Succeed())
+			delete(secret.Data, "type")
+			Expect(k8sClient.Update(ctx, secret)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb22", Namespace: testNamespace}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, serviceBindingLookupKey, sb); err != nil {
+					return ""
+				}
+				for _, c := range sb.Status.Conditions {
+					if c.Type == bindingv1beta1.ConditionServiceAvailable && c.Status == bindingv1beta1.ConditionFalse {
+						return c.Message
+					}
+				}
+				return ""
+			}, timeout, interval).Should(ContainSubstring("redis22b"))
+
+			bindingSecretLookupKey = types.NamespacedName{Name: "sb22-redis22b-binding", Namespace: testNamespace}
+			Expect(k8sClient.Get(ctx, bindingSecretLookupKey, bindingSecret)).Should(Succeed())
+			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
+			Expect(app.Spec.Template.Spec.Volumes).To(HaveLen(2))
+		})
+	})
+})
@@ -20,13 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/imdario/mergo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -35,7 +36,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -43,6 +46,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	servicebindingv1 "github.com/kubepreset/kubepreset/apis/servicebinding/v1"
 )
 
 // ServiceBindingRoot points to the environment variable in the container
@@ -65,12 +69,20 @@ type ProvisionedService struct {
 // ServiceBindingReconciler reconciles a ServiceBinding object
 type ServiceBindingReconciler struct {
 	client.Client
-	Log                logr.Logger
-	Scheme             *runtime.Scheme
-	mountPathDir       string
-	volumeNamePrefix   string
-	volumeName         string
-	unstructuredVolume map[string]interface{}
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	Schemas       *SchemaRegistry
+	Workloads     *WorkloadMappingRegistry
+	Catalog       *MappingCatalog
+	APIReader     client.Reader
+	ClusterDomain string
+	AuditOnly     bool
+	PodWebhook    bool
+	controller    controller.Controller
+	watchesMu     sync.Mutex
+	watches       map[schema.GroupVersionKind]bool
+	boundVolumes  []boundVolume
+	boundData     map[string][]byte
 }
 
 // AppNameSelectorInvariantErr represents the error when the application
@@ -98,8 +110,27 @@ func (err ContainersWithEnvsOrVolumeMountsErr) Error() string {
 	return fmt.Sprintf("Containers: %v, Envs: %v, VolumeMount: %v", err.Containers, err.Envs, err.VolumeMounts)
 }
 
+// OwnershipConflictErr represents the error when a resource the ServiceBinding
+// needs to manage already exists and is not controlled by the ServiceBinding
+type OwnershipConflictErr struct {
+	Kind string
+	Name string
+}
+
+// Error implements the built-in error interface
+func (err OwnershipConflictErr) Error() string {
+	return fmt.Sprintf("%s %q exists and is not owned by the ServiceBinding", err.Kind, err.Name)
+}
+
 // +kubebuilder:rbac:groups=service.binding,resources=servicebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=service.binding,resources=servicebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=clusterbindingtypes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=bindinggrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=clusterserviceresourcemappings,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps;services,verbs=get
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=servicebinding.io,resources=clusterworkloadresourcemappings,verbs=get;list;watch
 
 // Reconcile based on changes in the ServiceBinding CR or Provisioned Service Secret
 func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -149,7 +180,8 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			if err != nil {
 				return result, err
 			}
-			result, err = r.unbindApplications(ctx, log, req, sb, applications...)
+			// a suspended binding is left in the applications, it does not block the deletion
+			result, err = r.unbindApplications(ctx, log, req, &sb, applications...)
 			if err != nil {
 				return result, err
 			}
@@ -165,12 +197,75 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	if r.suspendReason(&sb) == "" {
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionSuspended)
+	}
+	if !sb.Spec.DryRun {
+		sb.Status.Previews = nil
+	}
+	if sb.Spec.Rollout == nil {
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionApplicationRolledOut)
+	}
+
+	if err := r.checkReferenceGrant(ctx, &sb); err != nil {
+		var notGrantedErr ReferenceNotGrantedErr
+		if !errors.As(err, &notGrantedErr) {
+			log.Error(err, "unable to check the BindingGrants of the service namespace")
+			return ctrl.Result{}, err
+		}
+		reason = err.Error()
+		log.Error(err, "service reference is not granted")
+		applications, result, err := r.getApplication(ctx, log, req, sb, "")
+		if err != nil {
+			return result, err
+		}
+		result, err = r.unbindApplications(ctx, log, req, &sb, applications...)
+		if err != nil {
+			return result, err
+		}
+		// the binding Secret must not outlive the grant it was copied under
+		if err := r.deleteBindingSecret(ctx, log, &sb); err != nil {
+			return ctrl.Result{}, err
+		}
+		conditionStatus = "False"
+		return r.setStatus(ctx, log, "", sb, conditionStatus, reason)
+	}
+
+	if sb.Spec.Service.Selector != nil {
+		return r.reconcileSelectedServices(ctx, log, req, sb)
+	}
+
 	var secretLookupKey client.ObjectKey
+	var psSecret *corev1.Secret
 
 	if sb.Spec.Service.Kind == "Secret" && sb.Spec.Service.APIVersion == "v1" {
-		secretLookupKey = client.ObjectKey{Name: sb.Spec.Service.Name, Namespace: req.NamespacedName.Namespace}
+		secretLookupKey = client.ObjectKey{Name: sb.Spec.Service.Name, Namespace: serviceNamespace(&sb)}
+	} else if isKubeService(&sb) {
+		data, err := r.kubeServiceBindingData(ctx, &sb)
+		if err != nil {
+			reason = "unable to bind the Service: " + err.Error()
+			log.Error(err, "unable to bind the Service")
+			conditionStatus = "False"
+			if _, err := r.setStatus(ctx, log, secretName, sb, conditionStatus, reason); err != nil {
+				return ctrl.Result{}, err
+			}
+			// the Service and its credentials Secret are not watched
+			return ctrl.Result{RequeueAfter: time.Minute * 1}, nil
+		}
+		secretLookupKey = client.ObjectKey{Name: sb.Spec.Service.Name, Namespace: serviceNamespace(&sb)}
+		psSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretLookupKey.Name, Namespace: secretLookupKey.Namespace},
+			Data:       data,
+		}
 	} else {
-		backingServiceCRLookupKey := client.ObjectKey{Name: sb.Spec.Service.Name, Namespace: req.NamespacedName.Namespace}
+		backingServiceGVK := schema.FromAPIVersionAndKind(sb.Spec.Service.APIVersion, sb.Spec.Service.Kind)
+		watching := true
+		if err := r.watchBackingService(backingServiceGVK); err != nil {
+			log.Error(err, "unable to watch the backing service kind", "GVK", backingServiceGVK)
+			watching = false
+		}
+
+		backingServiceCRLookupKey := client.ObjectKey{Name: sb.Spec.Service.Name, Namespace: serviceNamespace(&sb)}
 
 		backingServiceCR := &unstructured.Unstructured{
 			Object: map[string]interface{}{
@@ -193,7 +288,7 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				if err != nil {
 					return result, err
 				}
-				result, err = r.unbindApplications(ctx, log, req, sb, applications...)
+				result, err = r.unbindApplications(ctx, log, req, &sb, applications...)
 				if err != nil {
 					return result, err
 				}
@@ -206,9 +301,11 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				if _, err = r.setStatus(ctx, log, "", sb, conditionStatus, reason); err != nil {
 					return ctrl.Result{}, err
 				}
+				if watching {
+					// the watch on the backing service kind triggers reconciliation once it is created
+					return ctrl.Result{}, nil
+				}
 				// Requeue with a time interval is required as the Secret name is not available to reconcile
-				// In future, probably watching for provisioned services based on label can be introduced
-				// or a webhook can detect provisioned service resource change and trigger reconciliation
 				return ctrl.Result{RequeueAfter: time.Minute * 1}, nil
 			}
 		}
@@ -223,28 +320,61 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 		log.V(1).Info("completed mapping backing service with the provisioned service", "ProvisionedService", ps)
 
-		secretLookupKey = client.ObjectKey{Name: ps.Status.Binding.Name, Namespace: req.NamespacedName.Namespace}
+		secretLookupKey = client.ObjectKey{Name: ps.Status.Binding.Name, Namespace: serviceNamespace(&sb)}
+		if ps.Status.Binding.Name == "" {
+			// the backing service does not implement the provisioned service duck
+			// type, fall back to the ClusterServiceResourceMapping or the binding annotations
+			data, found, err := r.serviceResourceBindingData(ctx, log, backingServiceCR)
+			if err != nil {
+				reason = err.Error()
+				log.Error(err, "unable to bind the values of the backing service")
+				conditionStatus = "False"
+				return r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
+			}
+			if !found {
+				return r.setServiceUnavailable(ctx, log, secretName, sb, backingServiceGVK, watching)
+			}
+			secretLookupKey = client.ObjectKey{Name: backingServiceCR.GetName(), Namespace: serviceNamespace(&sb)}
+			psSecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretLookupKey.Name, Namespace: secretLookupKey.Namespace},
+				Data:       data,
+			}
+		}
 	}
-	psSecret := &corev1.Secret{}
+	sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionServiceAvailable)
+	synthesised := psSecret != nil
 
-	log.V(1).Info("retrieving the Secret object")
-	if err := r.Get(ctx, secretLookupKey, psSecret); err != nil {
-		reason = "unable to retrieve the Secret object"
-		log.Error(err, reason, "Secret Lookup Key", secretLookupKey, "Secret", psSecret)
-		// TODO: Unbind existing bindings
-		applications, result, err := r.getApplication(ctx, log, req, sb, psSecret.GetName())
-		if err != nil {
-			return result, err
-		}
-		result, err = r.unbindApplications(ctx, log, req, sb, applications...)
-		if err != nil {
-			return result, err
-		}
+	if !synthesised {
+		psSecret = &corev1.Secret{}
 
-		conditionStatus = "False"
-		return r.setStatus(ctx, log, secretLookupKey.Name, sb, conditionStatus, reason)
+		log.V(1).Info("retrieving the Secret object")
+		if err := r.Get(ctx, secretLookupKey, psSecret); err != nil {
+			reason = "unable to retrieve the Secret object"
+			log.Error(err, reason, "Secret Lookup Key", secretLookupKey, "Secret", psSecret)
+			// TODO: Unbind existing bindings
+			applications, result, err := r.getApplication(ctx, log, req, sb, psSecret.GetName())
+			if err != nil {
+				return result, err
+			}
+			result, err = r.unbindApplications(ctx, log, req, &sb, applications...)
+			if err != nil {
+				return result, err
+			}
+
+			conditionStatus = "False"
+			if result, err := r.setStatus(ctx, log, secretName, sb, conditionStatus, reason); err != nil {
+				return result, err
+			}
+			if sb.Spec.Service.Kind == "Secret" && sb.Spec.Service.APIVersion == "v1" {
+				// the Secret watch triggers reconciliation once a directly referenced Secret is created
+				return ctrl.Result{}, nil
+			}
+			// Requeue with a time interval is required as the Secret of a provisioned service
+			// is not known to the Secret watch until the binding Secret has been generated
+			return ctrl.Result{RequeueAfter: time.Minute * 1}, nil
+		}
+		log.V(2).Info("the secret object retrieved", "Secret", psSecret)
 	}
-	log.V(2).Info("the secret object retrieved", "Secret", psSecret)
 
 	if sb.Spec.Application.Name != "" && sb.Spec.Application.Selector != nil {
 		err := AppNameSelectorInvariantErr{
@@ -253,74 +383,103 @@ func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		log.Error(err, "Both application name and selector cannot be used together")
 		conditionStatus = "False"
 		reason = "application name and selector cannot be used together"
-		return r.setStatus(ctx, log, psSecret.Name, sb, conditionStatus, reason)
+		return r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
 	}
 
-	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: sb.Name}}
-	cm.Namespace = sb.Namespace
-	cm.Labels = sb.DeepCopy().GetLabels()
-	cm.Data = map[string]string{}
-	if sb.Spec.Type != "" {
-		cm.Data["type"] = sb.Spec.Type
+	if len(sb.Spec.Application.Kinds) > 0 && sb.Spec.Application.Selector == nil {
+		err := errors.New("application kinds require a selector")
+		log.Error(err, "application kinds can only be used with a selector")
+		conditionStatus = "False"
+		reason = "application kinds can only be used with a selector"
+		return r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
+	}
+
+	data := bindingData(&sb, psSecret)
+	if err := r.mergeSources(ctx, log, &sb, data); err != nil {
+		reason = "unable to merge the sources: " + err.Error()
+		log.Error(err, "unable to merge the sources")
+		conditionStatus = "False"
+		return r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
 	}
-	if sb.Spec.Provider != "" {
-		cm.Data["provider"] = sb.Spec.Provider
+	if err := applyMappings(sb.Spec.Mappings, data); err != nil {
+		reason = err.Error()
+		log.Error(err, "unable to render the mappings")
+		conditionStatus = "False"
+		return r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
+	}
+	if _, ok := data["type"]; !ok {
+		return ctrl.Result{}, errors.New("value for `type` not specified in the Secret resource or ServiceBinding resource")
 	}
-	if _, ok := psSecret.Data["type"]; !ok {
-		if sb.Spec.Type == "" {
-			return ctrl.Result{}, errors.New("value for `type` not specified in the Secret resource or ServiceBinding resource")
+
+	bindingSecret, err := r.reconcileBindingSecret(ctx, log, &sb, secretLookupKey, data)
+	if err != nil {
+		var ownershipErr OwnershipConflictErr
+		if errors.As(err, &ownershipErr) {
+			reason = "a Secret with the name of the binding Secret exists and is not owned by the ServiceBinding"
+			log.Error(err, reason)
+			conditionStatus = "False"
+			return r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
 		}
+		log.Error(err, "unable to create or update the binding Secret")
+		return ctrl.Result{}, err
 	}
-	cm.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(sb.GetObjectMeta(), sb.GroupVersionKind())}
-	log.V(1).Info("Creating ConfigMap resource for binding", "ConfigMap", cm)
-	if err := r.Create(ctx, cm); err != nil {
-		log.Error(err, "unable to create ConfigMap resource")
-		if err := r.Delete(ctx, cm); err != nil {
+	r.validateEntries(ctx, log, &sb, bindingSecret.Data)
+
+	// the binding data is projected from the binding Secret or a snapshot of it
+	projected := bindingSecret
+	if rollbackEnabled(&sb) {
+		projected, err = r.reconcileSnapshot(ctx, log, &sb, bindingSecret)
+		if err != nil {
+			log.Error(err, "unable to reconcile the snapshot of the binding data")
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{Requeue: true}, nil
-	}
-	log.V(1).Info("ConfigMap created", "ConfigMap", cm)
-
-	volumeNamePrefix := sb.Name
-	if len(volumeNamePrefix) > 56 {
-		volumeNamePrefix = volumeNamePrefix[:56]
-	}
-	r.volumeName = volumeNamePrefix + "-" + psSecret.GetResourceVersion()
-	r.mountPathDir = sb.Name
-	if sb.Spec.Name != "" {
-		r.mountPathDir = sb.Spec.Name
-	}
-	sp := &corev1.SecretProjection{
-		LocalObjectReference: corev1.LocalObjectReference{
-			Name: psSecret.Name,
-		}}
-	cmp := &corev1.ConfigMapProjection{
-		LocalObjectReference: corev1.LocalObjectReference{
-			Name: cm.Name,
-		}}
-	volumeProjection := &corev1.Volume{
-		Name: r.volumeName,
-		VolumeSource: corev1.VolumeSource{
-			Projected: &corev1.ProjectedVolumeSource{
-				Sources: []corev1.VolumeProjection{{Secret: sp}, {ConfigMap: cmp}},
-			},
-		},
+	} else if sb.Status.Snapshot != nil {
+		if err := r.pruneSnapshots(ctx, log, &sb, nil); err != nil {
+			log.Error(err, "unable to delete the snapshots of the binding data")
+			return ctrl.Result{}, err
+		}
+		sb.Status.Snapshot = nil
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionDegraded)
 	}
 
-	log.V(2).Info("converting the volumeProjection to an unstructured object", "Volume", volumeProjection)
-	var err error
-	r.unstructuredVolume, err = runtime.DefaultUnstructuredConverter.ToUnstructured(volumeProjection)
+	items, err := projectionItems(sb.Spec.Files, projected.Data)
+	if err != nil {
+		reason = err.Error()
+		log.Error(err, "unable to select the projected files")
+		conditionStatus = "False"
+		return r.setStatus(ctx, log, bindingSecret.Name, sb, conditionStatus, reason)
+	}
+	bv, err := newBoundVolume(volumeNamePrefix(&sb, 56)+volumeNameSuffix(&sb, projected), bindingDirectory(&sb), projected, items)
 	if err != nil {
 		log.Error(err, "unable to convert volumeProjection to an unstructured object")
 		return ctrl.Result{}, err
 	}
+	r.boundVolumes = []boundVolume{bv}
+	r.boundData = projected.Data
+
+	known, err := r.checkApplicationKind(log, &sb)
+	if err != nil {
+		log.Error(err, "unable to determine the RESTMapping of the application")
+		return ctrl.Result{}, err
+	}
+	if !known {
+		reason = "the kind of the application is not served by the cluster"
+		conditionStatus = "False"
+		// the watch on CustomResourceDefinitions triggers reconciliation once the kind is installed
+		return r.setStatus(ctx, log, bindingSecret.Name, sb, conditionStatus, reason)
+	}
 
 	applications, result, err := r.getApplication(ctx, log, req, sb, secretName)
 	if err != nil {
 		return result, err
 	}
-	return r.bindApplications(ctx, log, req, sb, psSecret, applications...)
+	result, err = r.bindApplications(ctx, log, req, sb, bindingSecret, applications...)
+	if err == nil && (synthesised || len(sb.Spec.Sources) > 0) && !result.Requeue && result.RequeueAfter == 0 {
+		// the Service, the sources, or the fields and resources named by the
+		// mapping or the binding annotations, are not watched, resync them periodically
+		result.RequeueAfter = time.Minute * 1
+	}
+	return result, err
 }
 
 type errorList []error
@@ -365,27 +524,30 @@ func (r *ServiceBindingReconciler) getApplication(ctx context.Context, log logr.
 	}
 
 	if sb.Spec.Application.Selector != nil {
-		applicationList := &unstructured.UnstructuredList{
-			Object: map[string]interface{}{
-				"kind":       sb.Spec.Application.Kind,
-				"apiVersion": sb.Spec.Application.APIVersion,
-				"metadata":   map[string]interface{}{},
-			},
-		}
+		for _, gvk := range applicationKinds(sb.Spec.Application) {
+			applicationList := &unstructured.UnstructuredList{
+				Object: map[string]interface{}{
+					"kind":       gvk.Kind,
+					"apiVersion": gvk.GroupVersion().String(),
+					"metadata":   map[string]interface{}{},
+				},
+			}
 
-		log.V(2).Info("retrieving the application objects", "Application", applicationList)
-		opts := &client.ListOptions{
-			LabelSelector: labels.Set(sb.Spec.Application.Selector.MatchLabels).AsSelector(),
-		}
-		if err := r.List(ctx, applicationList, opts); err != nil {
-			reason = "unable to retrieve application"
-			log.Error(err, reason)
-			conditionStatus = "False"
-			result, err := r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
-			return []unstructured.Unstructured{}, result, err
+			log.V(2).Info("retrieving the application objects", "Application", applicationList)
+			opts := &client.ListOptions{
+				Namespace:     req.NamespacedName.Namespace,
+				LabelSelector: labels.Set(sb.Spec.Application.Selector.MatchLabels).AsSelector(),
+			}
+			if err := r.List(ctx, applicationList, opts); err != nil {
+				reason = "unable to retrieve application"
+				log.Error(err, reason)
+				conditionStatus = "False"
+				result, err := r.setStatus(ctx, log, secretName, sb, conditionStatus, reason)
+				return []unstructured.Unstructured{}, result, err
+			}
+			log.V(1).Info("application objects retrieved", "Application", applicationList)
+			applications = append(applications, applicationList.Items...)
 		}
-		log.V(1).Info("application objects retrieved", "Application", applicationList)
-		applications = append(applications, applicationList.Items...)
 	}
 	if len(applications) == 0 {
 		// Requeue with a time interval is required as the applications is not available to reconcile
@@ -396,35 +558,184 @@ func (r *ServiceBindingReconciler) getApplication(ctx context.Context, log logr.
 	return applications, ctrl.Result{}, nil
 }
 
+// unbindApplications removes the volumes projected for the ServiceBinding,
+// their volume mounts and the environment variables injected for it from the
+// applications.  A suspended ServiceBinding reports the applications it would
+// unbind instead.
 func (r *ServiceBindingReconciler) unbindApplications(ctx context.Context, log logr.Logger, req ctrl.Request,
-	sb bindingv1beta1.ServiceBinding, applications ...unstructured.Unstructured) (ctrl.Result, error) {
+	sb *bindingv1beta1.ServiceBinding, applications ...unstructured.Unstructured) (ctrl.Result, error) {
+
+	suspendReason := r.suspendReason(sb)
+	pending := []string{}
+	mappings := map[schema.GroupVersionKind]*bindingv1beta1.ClusterApplicationResourceMapping{}
+
+	var el errorList
+	for _, application := range applications {
+		original := application.DeepCopy()
+		gvk := application.GroupVersionKind()
+		armObj, ok := mappings[gvk]
+		if !ok {
+			var err error
+			armObj, err = r.applicationResourceMapping(ctx, log, req, gvk)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			mappings[gvk] = armObj
+		}
+		containersPaths, envsPaths, volumeMountsPaths, volumesPath := applicationPaths(armObj, gvk)
+
+		injected := injectedEntriesOf(&application, sb)
+		owned := ownedVolumes(sb, injected, nil)
+		if err := removeBindingEntries(&application, volumesPath, owned); err != nil {
+			return ctrl.Result{}, err
+		}
+		if n := len(volumesPath); n >= 2 && volumesPath[n-2] == "spec" && volumesPath[n-1] == "volumes" {
+			annotationPath := append(append([]string{}, volumesPath[:n-2]...), "metadata", "annotations", rotationAnnotation(sb))
+			unstructured.RemoveNestedField(application.Object, annotationPath...)
+		}
+		for _, envsPath := range envsPaths {
+			env := injectedEnv(&application, sb, injected, "."+strings.Join(envsPath, "."))
+			if err := removeBindingEntries(&application, envsPath, env); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		for _, volumeMountsPath := range volumeMountsPaths {
+			if err := removeBindingEntries(&application, volumeMountsPath, owned); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		for _, containersPath := range containersPaths {
+			containers, _, err := unstructured.NestedSlice(application.Object, containersPath...)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			for i := range containers {
+				container, ok := containers[i].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				u := &unstructured.Unstructured{Object: container}
+				if err := removeBindingEntries(u, []string{"volumeMounts"}, owned); err != nil {
+					return ctrl.Result{}, err
+				}
+				name, _ := container["name"].(string)
+				if injected == nil && !selectedContainer(sb.Spec.Application.Containers, name) {
+					continue
+				}
+				if err := removeBindingEntries(u, []string{"env"}, injectedEnv(&application, sb, injected, name)); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			if len(containers) > 0 {
+				if err := unstructured.SetNestedSlice(application.Object, containers, containersPath...); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+		removeInjectedEntries(&application, sb)
+
+		if equality.Semantic.DeepEqual(original.Object, application.Object) {
+			continue
+		}
+		if suspendReason != "" {
+			log.V(0).Info("binding is suspended, not removing the binding from the application", "application", applicationRef(&application))
+			pending = append(pending, applicationRef(&application))
+			continue
+		}
+		log.V(1).Info("removing the binding from the application", "application", applicationRef(&application))
+		if err := r.Update(ctx, &application); err != nil {
+			log.Error(err, "unable to unbind the application", "application", applicationRef(&application))
+			el = append(el, err)
+		}
+	}
+	if suspendReason != "" {
+		setSuspendedUnbind(sb, suspendReason, pending)
+	}
+	if len(el) > 0 {
+		return ctrl.Result{}, el
+	}
 	return ctrl.Result{}, nil
 }
 
-func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log logr.Logger, req ctrl.Request,
-	sb bindingv1beta1.ServiceBinding, psSecret *corev1.Secret, applications ...unstructured.Unstructured) (ctrl.Result, error) {
+// applicationPaths returns the paths of the containers, of the env lists, of
+// the volume mounts and of the volumes of the application kind, from its
+// mapping when it has one
+func applicationPaths(armObj *bindingv1beta1.ClusterApplicationResourceMapping,
+	gvk schema.GroupVersionKind) ([][]string, [][]string, [][]string, []string) {
+
+	if armObj == nil {
+		return [][]string{
+			{"spec", "template", "spec", "containers"},
+			{"spec", "template", "spec", "initContainers"},
+		}, nil, nil, []string{"spec", "template", "spec", "volumes"}
+	}
+	containersPaths := [][]string{}
+	envsPaths := [][]string{}
+	volumeMountsPaths := [][]string{}
+	volumesPath := []string{"spec", "template", "spec", "volumes"}
+	for _, ver := range armObj.Spec.Versions {
+		if ver.Version == gvk.Version || ver.Version == "*" {
+			for _, containersPath := range ver.Containers {
+				containersPaths = append(containersPaths, strings.Split(containersPath[1:], "."))
+			}
+			for _, envsPath := range ver.Envs {
+				envsPaths = append(envsPaths, strings.Split(envsPath[1:], "."))
+			}
+			for _, volumeMountsPath := range ver.VolumeMounts {
+				volumeMountsPaths = append(volumeMountsPaths, strings.Split(volumeMountsPath[1:], "."))
+			}
+			volumesPath = strings.Split(ver.Volumes[1:], ".")
+			break
+		}
+	}
+	return containersPaths, envsPaths, volumeMountsPaths, volumesPath
+}
 
-	gvk := applications[0].GroupVersionKind()
-	gk := schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}
-	rm, err := r.RESTMapper().RESTMapping(gk, gvk.Version)
-	if err != nil {
-		log.Error(err, "unable to determine the RESTMapping")
-		return ctrl.Result{}, err
+func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log logr.Logger, req ctrl.Request,
+	sb bindingv1beta1.ServiceBinding, bindingSecret *corev1.Secret, applications ...unstructured.Unstructured) (ctrl.Result, error) {
+
+	if bindingMode(&sb) == bindingv1beta1.BindingModePod && !r.PodWebhook {
+		// nothing injects the binding into the Pods of the applications
+		reason := "Pod mode requires --enable-pod-webhook"
+		log.V(0).Info("the Pod mutating webhook is not served", "reason", reason)
+		var conditionStatus bindingv1beta1.ConditionStatus = "False"
+		return r.setStatus(ctx, log, bindingSecret.Name, sb, conditionStatus, reason)
 	}
 
-	armObj := &bindingv1beta1.ClusterApplicationResourceMapping{}
+	// the applications may be of several kinds, each with its own mapping
+	mappings := map[schema.GroupVersionKind]*bindingv1beta1.ClusterApplicationResourceMapping{}
 
-	log.V(2).Info("retrieving the ClusterApplicationResourceMapping objects", "ClusterApplicationResourceMapping", armObj)
-	armLookupKey := client.ObjectKey{Name: rm.Resource.Resource + "." + gvk.Group, Namespace: req.NamespacedName.Namespace}
-	armExists := true
-	if err := r.Get(ctx, armLookupKey, armObj); err != nil {
-		log.V(1).Info("unable to retrieve ClusterApplicationResourceMapping", "error", err)
-		armExists = false
-	}
-	log.V(1).Info("ClusterApplicationResourceMapping objects retrieved", "ClusterApplicationResourceMapping", armObj)
+	// a suspended binding reports the applications it would update instead
+	suspendReason := r.suspendReason(&sb)
+	pending := []string{}
+	previews := []bindingv1beta1.ApplicationPreview{}
+
+	// changed applications beyond the batch size are restarted in a later batch
+	maxConcurrent, delay := rotationBatch(&sb)
+	restarted := 0
+	deferred := []string{}
+	updated := []unstructured.Unstructured{}
 
 	var el errorList
+	updateFailed := false
 	for _, application := range applications {
+		if bindingMode(&sb) == bindingv1beta1.BindingModePod {
+			// the Pod mutating webhook injects the binding as the Pods are created
+			continue
+		}
+		original := application.DeepCopy()
+		gvk := application.GroupVersionKind()
+		armObj, ok := mappings[gvk]
+		if !ok {
+			var err error
+			armObj, err = r.applicationResourceMapping(ctx, log, req, gvk)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			mappings[gvk] = armObj
+		}
+		armExists := armObj != nil
+
 		containersPaths := [][]string{}
 		envsPaths := [][]string{}
 		volumeMountsPaths := [][]string{}
@@ -441,7 +752,7 @@ func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log log
 						reason := "a combination of envs and volumeMounts is mutually exclusive with containers"
 						log.Error(err, reason)
 						var conditionStatus bindingv1beta1.ConditionStatus = "False"
-						return r.setStatus(ctx, log, psSecret.Name, sb, conditionStatus, reason)
+						return r.setStatus(ctx, log, bindingSecret.Name, sb, conditionStatus, reason)
 					}
 					for _, containersPath := range ver.Containers {
 						containersPaths = append(containersPaths, strings.Split(containersPath[1:], "."))
@@ -475,25 +786,25 @@ func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log log
 		}
 		log.V(2).Info("Volumes values", "volumes", volumes)
 
-		volumeFound := false
-
-		for i, volume := range volumes {
-			log.V(2).Info("Volume", "volume", volume)
-			if strings.HasPrefix(volume.(map[string]interface{})["name"].(string), r.volumeNamePrefix) {
-				volumes[i] = r.unstructuredVolume
-				volumeFound = true
-			}
+		// the entries injected for the ServiceBinding are recorded on the application
+		injected := injectedEntriesOf(&application, &sb)
+		owned := ownedVolumes(&sb, injected, r.boundVolumes)
+		record := &injectedEntries{Env: map[string][]string{}}
+		for _, bv := range r.boundVolumes {
+			record.Volumes = append(record.Volumes, bv.name)
 		}
 
-		if !volumeFound {
-			volumes = append(volumes, r.unstructuredVolume)
-		}
+		volumes = replaceBindingVolumes(volumes, owned, r.boundVolumes)
 		log.V(2).Info("setting the updated volumes into the application using the unstructured object")
 		if err := unstructured.SetNestedSlice(application.Object, volumes, volumesPath...); err != nil {
 			return ctrl.Result{}, err
 		}
 		log.V(1).Info("application object after setting the update volume", "Application", application)
 
+		if err := setRotationAnnotation(&application, volumesPath, &sb, r.boundVolumes); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		if !envsOrVolumeMountsFound {
 			for _, containersPath := range containersPaths {
 				log.V(2).Info("referencing containers in an unstructured object")
@@ -534,47 +845,26 @@ func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log log
 
 					}
 
-					for _, e := range sb.Spec.Env {
-						c.Env = append(c.Env, corev1.EnvVar{
-							Name:  e.Name,
-							Value: string(psSecret.Data[e.Key]),
-						})
-
-					}
-					mountPath := ""
+					c.Env = setBindingEnv(c.Env, &sb, r.boundData, previousEnv(injected, c.Name))
+					root := ""
 					for _, e := range c.Env {
 						if e.Name == ServiceBindingRoot {
-							mountPath = path.Join(e.Value, r.mountPathDir)
+							root = e.Value
 							break
 						}
 					}
 
-					if mountPath == "" {
-						mountPath = path.Join("/bindings", r.mountPathDir)
+					rootAdded := root == ""
+					if rootAdded {
+						root = "/bindings"
 						c.Env = append(c.Env, corev1.EnvVar{
 							Name:  ServiceBindingRoot,
 							Value: "/bindings",
 						})
 					}
+					record.Env[c.Name] = injectedEnvNames(&application, &sb, injected, c.Name, rootAdded)
 
-					volumeMount := corev1.VolumeMount{
-						Name:      r.volumeName,
-						MountPath: mountPath,
-						ReadOnly:  true,
-					}
-
-					volumeMountFound := false
-					for j, vm := range c.VolumeMounts {
-						if strings.HasPrefix(vm.Name, r.volumeNamePrefix) {
-							c.VolumeMounts[j] = volumeMount
-							volumeMountFound = true
-							break
-						}
-					}
-
-					if !volumeMountFound {
-						c.VolumeMounts = append(c.VolumeMounts, volumeMount)
-					}
+					c.VolumeMounts = replaceBindingVolumeMounts(c.VolumeMounts, owned, root, r.boundVolumes)
 
 					nu, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
 					if err != nil {
@@ -594,7 +884,7 @@ func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log log
 			}
 		} else {
 
-			mountPath := ""
+			root := ""
 
 			for _, envsPath := range envsPaths {
 				log.V(2).Info("referencing env in an unstructured object")
@@ -616,26 +906,23 @@ func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log log
 
 				for _, e := range ev {
 					if e.Name == ServiceBindingRoot {
-						mountPath = path.Join(e.Value, r.mountPathDir)
+						root = e.Value
 						break
 					}
 				}
 
-				if mountPath == "" {
-					mountPath = path.Join("/bindings", r.mountPathDir)
+				rootAdded := root == ""
+				if rootAdded {
+					root = "/bindings"
 					ev = append(ev, corev1.EnvVar{
 						Name:  ServiceBindingRoot,
 						Value: "/bindings",
 					})
 				}
+				key := "." + strings.Join(envsPath, ".")
+				record.Env[key] = injectedEnvNames(&application, &sb, injected, key, rootAdded)
 
-				for _, e := range sb.Spec.Env {
-					ev = append(ev, corev1.EnvVar{
-						Name:  e.Name,
-						Value: string(psSecret.Data[e.Key]),
-					})
-
-				}
+				ev = setBindingEnv(ev, &sb, r.boundData, previousEnv(injected, key))
 
 				evUnstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ev)
 				if err != nil {
@@ -667,24 +954,10 @@ func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log log
 					return ctrl.Result{}, err
 				}
 
-				volumeMountSB := corev1.VolumeMount{
-					Name:      r.volumeName,
-					MountPath: mountPath,
-					ReadOnly:  true,
-				}
-
-				volumeMountFound := false
-				for j, v := range vm {
-					if strings.HasPrefix(v.Name, r.volumeNamePrefix) {
-						vm[j] = volumeMountSB
-						volumeMountFound = true
-						break
-					}
-				}
-
-				if !volumeMountFound {
-					vm = append(vm, volumeMountSB)
+				if root == "" {
+					root = "/bindings"
 				}
+				vm = replaceBindingVolumeMounts(vm, owned, root, r.boundVolumes)
 
 				vmUnstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(vm)
 				if err != nil {
@@ -700,52 +973,176 @@ func (r *ServiceBindingReconciler) bindApplications(ctx context.Context, log log
 			}
 		}
 
-		var conditionStatus bindingv1beta1.ConditionStatus
-		var reason string
+		if len(record.Env) == 0 {
+			record.Env = nil
+		}
+		if err := setInjectedEntries(&application, &sb, record); err != nil {
+			return ctrl.Result{}, err
+		}
 
-		conditionStatus = "True"
+		if suspendReason != "" {
+			if !equality.Semantic.DeepEqual(original.Object, application.Object) {
+				log.V(0).Info("binding is suspended, not updating the application", "application", applicationRef(&application))
+				pending = append(pending, applicationRef(&application))
+				if sb.Spec.DryRun {
+					previews = append(previews, r.previewApplication(ctx, log, &sb, original, &application))
+				}
+			}
+			continue
+		}
+
+		changed := !equality.Semantic.DeepEqual(original.Object, application.Object)
+		if changed && maxConcurrent > 0 && restarted >= maxConcurrent {
+			log.V(1).Info("deferring the update of the application to a later batch", "application", applicationRef(&application))
+			deferred = append(deferred, applicationRef(&application))
+			continue
+		}
 
 		log.V(2).Info("updating the application with updated volumes and volumeMounts")
 		if err := r.Update(ctx, &application); err != nil {
 			log.Error(err, "unable to update the application", "application", application)
-			conditionStatus = "False"
-			reason = "application update failed"
+			updateFailed = true
+		} else {
+			updated = append(updated, application)
+			if changed {
+				restarted++
+			}
 		}
+	}
 
-		_, err = r.setStatus(ctx, log, psSecret.Name, sb, conditionStatus, reason)
-		if err != nil {
-			el = append(el, err)
+	var conditionStatus bindingv1beta1.ConditionStatus = "True"
+	var reason string
+	if updateFailed {
+		conditionStatus = "False"
+		reason = "application update failed"
+	}
+	rollout := rolledOut
+	if sb.Spec.Rollout != nil && suspendReason == "" && bindingMode(&sb) == bindingv1beta1.BindingModeWorkload {
+		if restarted > 0 {
+			// the changed applications start a new rollout
+			sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionApplicationRolledOut)
+		}
+		rollout = setRolloutCondition(&sb, updated)
+		if rollout == rolledOut && len(deferred) == 0 {
+			completeRollout(log, &sb)
+		}
+		if rollout == rolloutFailed && rollbackEnabled(&sb) && rollback(log, &sb) {
+			conditionStatus = "False"
+			reason = "the binding was rolled back after the applications failed to roll out"
+			if _, err := r.setStatus(ctx, log, bindingSecret.Name, sb, conditionStatus, reason); err != nil {
+				return ctrl.Result{}, err
+			}
+			// project the previous snapshot into the applications
+			return ctrl.Result{Requeue: true}, nil
+		}
+		if !updateFailed {
+			switch rollout {
+			case rolloutFailed:
+				conditionStatus = "False"
+				reason = "the applications failed to roll out"
+			case rolloutProgressing:
+				conditionStatus = "Unknown"
+				reason = "waiting for the applications to roll out"
+			}
 		}
 	}
+	if len(deferred) > 0 && !updateFailed {
+		conditionStatus = "Unknown"
+		reason = fmt.Sprintf("restarting the applications in batches, waiting to update %s", strings.Join(deferred, ", "))
+	}
+	if suspendReason != "" {
+		setSuspended(&sb, suspendReason, pending)
+		if sb.Spec.DryRun {
+			sb.Status.Previews = previews
+		}
+		conditionStatus = "False"
+		reason = "the applications are not updated while the binding is suspended"
+	}
+	if _, err := r.setStatus(ctx, log, bindingSecret.Name, sb, conditionStatus, reason); err != nil {
+		el = append(el, err)
+	}
 	if len(el) > 0 {
 		return ctrl.Result{}, el
 	}
+	if len(deferred) > 0 {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+	if rollout != rolledOut {
+		// the applications are not watched, check their rollout periodically
+		return ctrl.Result{RequeueAfter: rolloutPollInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
-func (r *ServiceBindingReconciler) setStatus(ctx context.Context, log logr.Logger, secretName string,
-	sb bindingv1beta1.ServiceBinding, conditionStatus bindingv1beta1.ConditionStatus, reason string) (ctrl.Result, error) {
+// applicationResourceMapping returns the mapping of the application kind, nil
+// when the kind has none and the PodSpec-able defaults apply.  A
+// ClusterApplicationResourceMapping takes precedence over a
+// ClusterWorkloadResourceMapping, the built-in catalog and the mapping derived
+// from the CustomResourceDefinition, in that order.
+func (r *ServiceBindingReconciler) applicationResourceMapping(ctx context.Context, log logr.Logger, req ctrl.Request,
+	gvk schema.GroupVersionKind) (*bindingv1beta1.ClusterApplicationResourceMapping, error) {
+
+	gk := schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}
+	rm, err := r.RESTMapper().RESTMapping(gk, gvk.Version)
+	if err != nil {
+		log.Error(err, "unable to determine the RESTMapping")
+		return nil, err
+	}
 
-	sb.Status.Binding = &corev1.LocalObjectReference{Name: secretName}
+	armObj := &bindingv1beta1.ClusterApplicationResourceMapping{}
 
-	conditionFound := false
-	for k, cond := range sb.Status.Conditions {
-		if cond.Type == bindingv1beta1.ConditionReady {
-			cond.Status = conditionStatus
-			sb.Status.Conditions[k] = cond
-			conditionFound = true
+	log.V(2).Info("retrieving the ClusterApplicationResourceMapping objects", "ClusterApplicationResourceMapping", armObj)
+	armLookupKey := client.ObjectKey{Name: rm.Resource.Resource + "." + gvk.Group, Namespace: req.NamespacedName.Namespace}
+	armExists := true
+	if err := r.Get(ctx, armLookupKey, armObj); err != nil {
+		log.V(1).Info("unable to retrieve ClusterApplicationResourceMapping", "error", err)
+		armExists = false
+
+		// fall back to the servicebinding.io/v1 ClusterWorkloadResourceMapping
+		cwrmObj := &servicebindingv1.ClusterWorkloadResourceMapping{}
+		if err := r.Get(ctx, client.ObjectKey{Name: armLookupKey.Name}, cwrmObj); err != nil {
+			log.V(1).Info("unable to retrieve ClusterWorkloadResourceMapping", "error", err)
+		} else if err := cwrmObj.ConvertTo(armObj); err != nil {
+			log.Error(err, "unable to convert the ClusterWorkloadResourceMapping")
+		} else {
+			armExists = true
 		}
 	}
-
-	if !conditionFound {
-		c := bindingv1beta1.Condition{
-			LastTransitionTime: metav1.NewTime(time.Now()),
-			Type:               bindingv1beta1.ConditionReady,
-			Status:             conditionStatus,
-			Reason:             reason,
+	if !armExists {
+		// fall back to the built-in workload kinds and the enabled entries of the catalog
+		if spec, found := r.Catalog.Lookup(armLookupKey.Name); found {
+			armObj.Spec = spec
+			armExists = true
 		}
-		sb.Status.Conditions = append(sb.Status.Conditions, c)
 	}
+	if !armExists {
+		// fall back to the mapping derived from the CustomResourceDefinition schema
+		if spec, found := r.discoverWorkloadMapping(ctx, log, gvk); found {
+			armObj.Spec = spec
+			armExists = true
+		}
+	}
+	if !armExists {
+		return nil, nil
+	}
+	log.V(1).Info("ClusterApplicationResourceMapping objects retrieved", "ClusterApplicationResourceMapping", armObj)
+	return armObj, nil
+}
+
+func (r *ServiceBindingReconciler) setStatus(ctx context.Context, log logr.Logger, secretName string,
+	sb bindingv1beta1.ServiceBinding, conditionStatus bindingv1beta1.ConditionStatus, reason string) (ctrl.Result, error) {
+
+	sb.Status.Binding = nil
+	if secretName != "" {
+		sb.Status.Binding = &corev1.LocalObjectReference{Name: secretName}
+	}
+	sb.Status.Mode = bindingMode(&sb)
+
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, bindingv1beta1.Condition{
+		Type:   bindingv1beta1.ConditionReady,
+		Status: conditionStatus,
+		Reason: reason,
+	})
 
 	log.V(2).Info("updating the service binding status")
 	if err := r.Status().Update(ctx, &sb); err != nil {
@@ -757,6 +1154,42 @@ func (r *ServiceBindingReconciler) setStatus(ctx context.Context, log logr.Logge
 	return ctrl.Result{}, nil
 }
 
+// setServiceUnavailable reports a backing service that does not provide a
+// binding Secret.  Kinds that cannot implement the provisioned service duck
+// type are not requeued, others are waited for through the watch on the kind.
+func (r *ServiceBindingReconciler) setServiceUnavailable(ctx context.Context, log logr.Logger, secretName string,
+	sb bindingv1beta1.ServiceBinding, gvk schema.GroupVersionKind, watching bool) (ctrl.Result, error) {
+
+	canProvide, err := r.canProvideBinding(ctx, gvk)
+	if err != nil {
+		log.Error(err, "unable to check the schema of the backing service kind", "GVK", gvk)
+		return ctrl.Result{}, err
+	}
+
+	c := bindingv1beta1.Condition{
+		Type:    bindingv1beta1.ConditionServiceAvailable,
+		Status:  bindingv1beta1.ConditionFalse,
+		Reason:  bindingv1beta1.ReasonServiceNotReady,
+		Message: "waiting for the backing service to set status.binding.name",
+	}
+	if !canProvide {
+		c.Reason = bindingv1beta1.ReasonNotProvisionedService
+		c.Message = fmt.Sprintf("%s does not implement the provisioned service duck type; "+
+			"add a ClusterServiceResourceMapping or binding annotations for it", gvk.GroupKind())
+	}
+	log.V(0).Info("backing service does not provide a binding Secret", "reason", c.Reason)
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, c)
+
+	var conditionStatus bindingv1beta1.ConditionStatus = "False"
+	if _, err := r.setStatus(ctx, log, secretName, sb, conditionStatus, c.Message); err != nil {
+		return ctrl.Result{}, err
+	}
+	if !canProvide || watching {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: time.Minute * 1}, nil
+}
+
 func containsString(slice []string, s string) bool {
 	for _, item := range slice {
 		if item == s {
@@ -769,14 +1202,79 @@ func containsString(slice []string, s string) bool {
 // SetupWithManager setup controller with manager
 func (r *ServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	mapSecretToServiceBinding := func(a client.Object) []reconcile.Request {
+		ctx := context.Background()
+		reply := []reconcile.Request{}
+
+		// binding Secrets generated from the changed Secret
+		sourceKey := types.NamespacedName{Namespace: a.GetNamespace(), Name: a.GetName()}.String()
+		bindingSecrets := &corev1.SecretList{}
+		if err := r.List(ctx, bindingSecrets, client.HasLabels{BindingSecretLabel}); err == nil {
+			for i := range bindingSecrets.Items {
+				bs := &bindingSecrets.Items[i]
+				if bs.Annotations[SourceSecretAnnotation] != sourceKey {
+					continue
+				}
+				if owner := metav1.GetControllerOf(bs); owner != nil && owner.Kind == "ServiceBinding" {
+					reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+						Namespace: bs.Namespace,
+						Name:      owner.Name,
+					}})
+				}
+			}
+		}
+
+		// ServiceBindings projecting or directly referring the changed Secret,
+		// which may be in another namespace than the ServiceBinding
+		serviceBindings := &bindingv1beta1.ServiceBindingList{}
+		if err := r.List(ctx, serviceBindings); err != nil {
+			return reply
+		}
+		for i := range serviceBindings.Items {
+			sb := &serviceBindings.Items[i]
+			projected := sb.Namespace == a.GetNamespace() && sb.Status.Binding != nil && sb.Status.Binding.Name == a.GetName()
+			referred := sb.Spec.Service != nil && sb.Spec.Service.Kind == "Secret" &&
+				sb.Spec.Service.APIVersion == "v1" && sb.Spec.Service.Name == a.GetName() &&
+				serviceNamespace(sb) == a.GetNamespace()
+			if projected || referred {
+				reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: sb.Namespace,
+					Name:      sb.Name,
+				}})
+			}
+		}
+		return reply
+	}
+
+	mapBindingTypeToServiceBinding := func(a client.Object) []reconcile.Request {
+		reply := []reconcile.Request{}
+		bindingSecrets := &corev1.SecretList{}
+		if err := r.List(context.Background(), bindingSecrets, client.HasLabels{BindingSecretLabel}); err != nil {
+			return reply
+		}
+		for i := range bindingSecrets.Items {
+			bs := &bindingSecrets.Items[i]
+			if string(bs.Data["type"]) != a.GetName() {
+				continue
+			}
+			if owner := metav1.GetControllerOf(bs); owner != nil && owner.Kind == "ServiceBinding" {
+				reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: bs.Namespace,
+					Name:      owner.Name,
+				}})
+			}
+		}
+		return reply
+	}
+
+	mapGrantToServiceBinding := func(a client.Object) []reconcile.Request {
+		reply := []reconcile.Request{}
 		serviceBindings := &bindingv1beta1.ServiceBindingList{}
-		opts := &client.ListOptions{}
-		if err := r.List(context.Background(), serviceBindings, opts); err != nil {
-			return []reconcile.Request{}
+		if err := r.List(context.Background(), serviceBindings); err != nil {
+			return reply
 		}
-		reply := make([]reconcile.Request, 0, len(serviceBindings.Items))
-		for _, sb := range serviceBindings.Items {
-			if sb.Status.Binding != nil && sb.Status.Binding.Name == a.GetName() {
+		for i := range serviceBindings.Items {
+			sb := &serviceBindings.Items[i]
+			if sb.Spec.Service != nil && sb.Namespace != a.GetNamespace() && serviceNamespace(sb) == a.GetNamespace() {
 				reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
 					Namespace: sb.Namespace,
 					Name:      sb.Name,
@@ -786,11 +1284,35 @@ func (r *ServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return reply
 	}
 
+	if r.Schemas == nil {
+		r.Schemas = NewSchemaRegistry()
+	}
+	if r.Workloads == nil {
+		r.Workloads = NewWorkloadMappingRegistry()
+	}
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+
 	genPred := predicate.GenerationChangedPredicate{}
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&bindingv1beta1.ServiceBinding{}).
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&bindingv1beta1.ServiceBinding{}, builder.WithPredicates(genPred)).
 		Watches(&source.Kind{Type: &corev1.Secret{}},
 			handler.EnqueueRequestsFromMapFunc(mapSecretToServiceBinding)).
-		WithEventFilter(genPred).
-		Complete(r)
+		Watches(&source.Kind{Type: &bindingv1beta1.ClusterBindingType{}},
+			handler.EnqueueRequestsFromMapFunc(mapBindingTypeToServiceBinding)).
+		Watches(&source.Kind{Type: &bindingv1beta1.BindingGrant{}},
+			handler.EnqueueRequestsFromMapFunc(mapGrantToServiceBinding)).
+		Watches(&source.Kind{Type: crd},
+			handler.EnqueueRequestsFromMapFunc(r.mapCRDToServiceBinding)).
+		Watches(&source.Kind{Type: crd.DeepCopy()}, r.workloadMappingHandler()).
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.controller = c
+	return nil
 }
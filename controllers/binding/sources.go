@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// mergeSources merges the entries of the sources of the ServiceBinding into
+// the binding entries.  Entries already present take precedence and every
+// entry with a different value in a source of lower precedence is reported
+// through the SourcesMerged condition.
+func (r *ServiceBindingReconciler) mergeSources(ctx context.Context, log logr.Logger,
+	sb *bindingv1beta1.ServiceBinding, data map[string][]byte) error {
+
+	if len(sb.Spec.Sources) == 0 {
+		sb.Status.Conditions = removeCondition(sb.Status.Conditions, bindingv1beta1.ConditionSourcesMerged)
+		return nil
+	}
+
+	// origin records where each entry came from to describe conflicts
+	origin := map[string]string{}
+	for k := range data {
+		origin[k] = "service"
+	}
+
+	conflicts := []string{}
+	for _, src := range sb.Spec.Sources {
+		entries, err := r.sourceEntries(ctx, sb.Namespace, src)
+		if err != nil {
+			return err
+		}
+		name := src.Kind + "/" + src.Name
+		for _, k := range sortedKeys(entries) {
+			v := entries[k]
+			if existing, ok := data[k]; ok {
+				if !bytes.Equal(existing, v) {
+					conflicts = append(conflicts, fmt.Sprintf("%q from %s is overridden by %s", k, name, origin[k]))
+				}
+				continue
+			}
+			data[k] = v
+			origin[k] = name
+		}
+	}
+
+	c := bindingv1beta1.Condition{
+		Type:   bindingv1beta1.ConditionSourcesMerged,
+		Status: bindingv1beta1.ConditionTrue,
+	}
+	if len(conflicts) > 0 {
+		log.V(0).Info("sources have conflicting entries", "conflicts", conflicts)
+		c.Status = bindingv1beta1.ConditionFalse
+		c.Reason = "sources have conflicting entries"
+		c.Message = strings.Join(conflicts, "; ")
+	}
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, c)
+	return nil
+}
+
+// sourceEntries returns the selected entries of a Secret or ConfigMap source
+func (r *ServiceBindingReconciler) sourceEntries(ctx context.Context, namespace string,
+	src bindingv1beta1.Source) (map[string][]byte, error) {
+
+	key := client.ObjectKey{Name: src.Name, Namespace: namespace}
+	entries := map[string][]byte{}
+	switch src.Kind {
+	case "Secret":
+		secret := &corev1.Secret{}
+		if err := r.APIReader.Get(ctx, key, secret); err != nil {
+			return nil, err
+		}
+		for k, v := range secret.Data {
+			entries[k] = v
+		}
+	case "ConfigMap":
+		configMap := &corev1.ConfigMap{}
+		if err := r.APIReader.Get(ctx, key, configMap); err != nil {
+			return nil, err
+		}
+		for k, v := range configMap.BinaryData {
+			entries[k] = v
+		}
+		for k, v := range configMap.Data {
+			entries[k] = []byte(v)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported source kind %q", src.Kind)
+	}
+
+	if len(src.Keys) == 0 {
+		return entries, nil
+	}
+	selected := make(map[string][]byte, len(src.Keys))
+	for _, k := range src.Keys {
+		v, ok := entries[k]
+		if !ok {
+			return nil, fmt.Errorf("%s %q has no entry %q", src.Kind, src.Name, k)
+		}
+		selected[k] = v
+	}
+	return selected, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Binding Sources:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the ServiceBinding has Secret and ConfigMap sources", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb16",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb16", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			for _, obj := range []client.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret16", Namespace: testNamespace}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret16-credentials", Namespace: testNamespace}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "configmap16", Namespace: testNamespace}},
+			} {
+				err := k8sClient.Delete(ctx, obj, client.GracePeriodSeconds(0))
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should merge the sources and report the conflicting entries", func() {
+			ctx := context.Background()
+
+			By("Creating the Secrets and the ConfigMap")
+			for _, obj := range []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret16", Namespace: testNamespace},
+					StringData: map[string]string{
+						"type": "custom",
+						"host": "primary.example.org",
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret16-credentials", Namespace: testNamespace},
+					StringData: map[string]string{
+						"username": "guest",
+						"password": "password",
+					},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "configmap16", Namespace: testNamespace},
+					Data: map[string]string{
+						"host":     "replica.example.org",
+						"port":     "5432",
+						"username": "admin",
+					},
+				},
+			} {
+				Expect(k8sClient.Create(ctx, obj)).Should(Succeed())
+			}
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb16",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app16",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret16",
+					},
+					Sources: []bindingv1beta1.Source{
+						{Kind: "Secret", Name: "secret16-credentials"},
+						{Kind: "ConfigMap", Name: "configmap16"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			bindingSecretLookupKey := types.NamespacedName{Name: "sb16-binding", Namespace: testNamespace}
+			bindingSecret := &corev1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, bindingSecretLookupKey, bindingSecret)
+			}, timeout, interval).Should(Succeed())
+
+			Expect(string(bindingSecret.Data["host"])).To(Equal("primary.example.org"))
+			Expect(string(bindingSecret.Data["port"])).To(Equal("5432"))
+			Expect(string(bindingSecret.Data["username"])).To(Equal("guest"))
+			Expect(string(bindingSecret.Data["password"])).To(Equal("password"))
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb16", Namespace: testNamespace}
+			createdServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			var sourcesMerged *bindingv1beta1.Condition
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, createdServiceBinding)
+				if err != nil {
+					return false
+				}
+				for i, condition := range createdServiceBinding.Status.Conditions {
+					if condition.Type == bindingv1beta1.ConditionSourcesMerged &&
+						condition.Status == bindingv1beta1.ConditionFalse {
+						sourcesMerged = &createdServiceBinding.Status.Conditions[i]
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(sourcesMerged.Message).To(ContainSubstring(`"host" from ConfigMap/configmap16 is overridden by service`))
+			Expect(sourcesMerged.Message).To(ContainSubstring(`"username" from ConfigMap/configmap16 is overridden by Secret/secret16-credentials`))
+		})
+	})
+})
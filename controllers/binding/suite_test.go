@@ -29,6 +29,7 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	custompod "github.com/kubepreset/custompod/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
 	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -40,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	servicebindingv1 "github.com/kubepreset/kubepreset/apis/servicebinding/v1"
 	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
 	// +kubebuilder:scaffold:imports
 )
@@ -53,6 +55,7 @@ var cfg *rest.Config
 var k8sClient client.Client
 var testEnv *envtest.Environment
 var k8sManager manager.Manager
+var workloads *bindingcontrollers.WorkloadMappingRegistry
 
 //logLevel hold the current log level
 var logLevel zapcore.Level
@@ -104,6 +107,9 @@ var _ = BeforeSuite(func() {
 	err = bindingv1beta1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = servicebindingv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = custompod.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -114,9 +120,29 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
+	catalog, err := bindingcontrollers.NewMappingCatalog("kubepreset-custompods")
+	Expect(err).ToNot(HaveOccurred())
+
+	workloads = bindingcontrollers.NewWorkloadMappingRegistry()
 	err = (&bindingcontrollers.ServiceBindingReconciler{
+		Client:     k8sManager.GetClient(),
+		Log:        ctrl.Log.WithName("bindingcontrollers.servicebinding").WithName("ServiceBinding"),
+		Workloads:  workloads,
+		Catalog:    catalog,
+		PodWebhook: true,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&bindingcontrollers.ClusterServiceBindingReconciler{
+		Client: k8sManager.GetClient(),
+		Log:    ctrl.Log.WithName("bindingcontrollers.clusterservicebinding").WithName("ClusterServiceBinding"),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&bindingcontrollers.WorkloadReconciler{
 		Client: k8sManager.GetClient(),
-		Log:    ctrl.Log.WithName("bindingcontrollers.servicebinding").WithName("ServiceBinding"),
+		Log:    ctrl.Log.WithName("bindingcontrollers.workload").WithName("Deployment"),
+		Kind:   appsv1.SchemeGroupVersion.WithKind("Deployment"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
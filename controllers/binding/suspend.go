@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// suspendReason returns the reason the applications of the ServiceBinding are
+// not updated, empty when they are
+func (r *ServiceBindingReconciler) suspendReason(sb *bindingv1beta1.ServiceBinding) string {
+	if r.AuditOnly {
+		return bindingv1beta1.ReasonAuditOnly
+	}
+	if sb.Spec.DryRun {
+		return bindingv1beta1.ReasonDryRun
+	}
+	if sb.Spec.Suspend {
+		return bindingv1beta1.ReasonSuspended
+	}
+	return ""
+}
+
+// setSuspended reports the applications a suspended ServiceBinding would update
+// through the Suspended condition
+func setSuspended(sb *bindingv1beta1.ServiceBinding, reason string, pending []string) {
+	message := "no application would be updated"
+	if len(pending) > 0 {
+		message = "would update " + strings.Join(pending, ", ")
+	}
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, bindingv1beta1.Condition{
+		Type:    bindingv1beta1.ConditionSuspended,
+		Status:  bindingv1beta1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// applicationRef returns the kind and name identifying the application in status
+func applicationRef(application *unstructured.Unstructured) string {
+	return application.GetKind() + "/" + application.GetName()
+}
+
+// setSuspendedUnbind reports the applications a suspended ServiceBinding would
+// remove its binding from through the Suspended condition
+func setSuspendedUnbind(sb *bindingv1beta1.ServiceBinding, reason string, pending []string) {
+	message := "the binding would be removed from no application"
+	if len(pending) > 0 {
+		message = "would remove the binding from " + strings.Join(pending, ", ")
+	}
+	sb.Status.Conditions = setCondition(sb.Status.Conditions, bindingv1beta1.Condition{
+		Type:    bindingv1beta1.ConditionSuspended,
+		Status:  bindingv1beta1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Unbinding:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When deleting a ServiceBinding", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app32",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret32",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should remove only the volumes and environment variables injected for it", func() {
+			ctx := context.Background()
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret32",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "mysql",
+					"password": "secret",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating an application with a volume named after the ServiceBinding")
+			matchLabels := map[string]string{
+				"environment": "test32",
+			}
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app32",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+								Env: []corev1.EnvVar{{
+									Name:  "LOG_LEVEL",
+									Value: "debug",
+								}},
+								VolumeMounts: []corev1.VolumeMount{{
+									Name:      "sb32-data",
+									MountPath: "/data",
+								}},
+							}},
+							Volumes: []corev1.Volume{{
+								Name: "sb32-data",
+								VolumeSource: corev1.VolumeSource{
+									EmptyDir: &corev1.EmptyDirVolumeSource{},
+								},
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			By("Creating ServiceBinding")
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb32",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app32",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret32",
+					},
+					Env: []bindingv1beta1.Environment{{
+						Name: "DB_PASSWORD",
+						Key:  "password",
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			applicationLookupKey := types.NamespacedName{Name: "app32", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return len(app.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(2))
+
+			By("Deleting ServiceBinding")
+			Expect(k8sClient.Delete(ctx, sb)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb32", Namespace: testNamespace}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, &bindingv1beta1.ServiceBinding{})
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Get(ctx, applicationLookupKey, app)).Should(Succeed())
+			Expect(app.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(app.Spec.Template.Spec.Volumes[0].Name).To(Equal("sb32-data"))
+			container := app.Spec.Template.Spec.Containers[0]
+			Expect(container.VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: "sb32-data", MountPath: "/data"}}))
+			Expect(container.Env).To(Equal([]corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}))
+			Expect(app.Annotations).NotTo(HaveKey("binding.kubepreset.dev/injected-sb32"))
+		})
+	})
+})
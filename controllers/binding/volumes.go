@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// boundVolume is a binding Secret projected into the applications, and the
+// directory under $SERVICE_BINDING_ROOT it is mounted at
+type boundVolume struct {
+	name         string
+	mountPathDir string
+	volume       map[string]interface{}
+	dataHash     string
+}
+
+// volumeNamePrefix returns the prefix of the names of the volumes projected for
+// the ServiceBinding, from its name cut to max characters
+func volumeNamePrefix(sb *bindingv1beta1.ServiceBinding, max int) string {
+	prefix := sb.Name
+	if len(prefix) > max {
+		prefix = prefix[:max]
+	}
+	return prefix + "-"
+}
+
+// bindingDirectory returns the name of the directory the binding is mounted
+// at under $SERVICE_BINDING_ROOT
+func bindingDirectory(sb *bindingv1beta1.ServiceBinding) string {
+	if sb.Spec.Name != "" {
+		return sb.Spec.Name
+	}
+	return sb.Name
+}
+
+// newBoundVolume returns the projected volume of the binding Secret
+func newBoundVolume(name, mountPathDir string, secret *corev1.Secret, items []corev1.KeyToPath) (boundVolume, error) {
+	sp := &corev1.SecretProjection{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: secret.Name,
+		},
+		Items: items,
+	}
+	volumeProjection := &corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{Secret: sp}},
+			},
+		},
+	}
+	volume, err := runtime.DefaultUnstructuredConverter.ToUnstructured(volumeProjection)
+	if err != nil {
+		return boundVolume{}, err
+	}
+	return boundVolume{name: name, mountPathDir: mountPathDir, volume: volume, dataHash: dataHash(secret.Data)}, nil
+}
+
+// replaceBindingVolumes replaces the volumes of previous reconciliations,
+// matched by owned, by the bound volumes.  The bound volumes take the place of
+// the first replaced volume so the other volumes keep their order.
+func replaceBindingVolumes(volumes []interface{}, owned func(string) bool, bound []boundVolume) []interface{} {
+	updated := make([]interface{}, 0, len(volumes)+len(bound))
+	inserted := false
+	for _, volume := range volumes {
+		if v, ok := volume.(map[string]interface{}); ok {
+			if name, _ := v["name"].(string); owned(name) {
+				if !inserted {
+					for _, bv := range bound {
+						updated = append(updated, bv.volume)
+					}
+					inserted = true
+				}
+				continue
+			}
+		}
+		updated = append(updated, volume)
+	}
+	if !inserted {
+		for _, bv := range bound {
+			updated = append(updated, bv.volume)
+		}
+	}
+	return updated
+}
+
+// replaceBindingVolumeMounts replaces the volume mounts of previous
+// reconciliations, matched by owned, by the mounts of the bound volumes under
+// the root directory
+func replaceBindingVolumeMounts(mounts []corev1.VolumeMount, owned func(string) bool, root string,
+	bound []boundVolume) []corev1.VolumeMount {
+
+	boundMounts := make([]corev1.VolumeMount, 0, len(bound))
+	for _, bv := range bound {
+		boundMounts = append(boundMounts, corev1.VolumeMount{
+			Name:      bv.name,
+			MountPath: path.Join(root, bv.mountPathDir),
+			ReadOnly:  true,
+		})
+	}
+
+	updated := make([]corev1.VolumeMount, 0, len(mounts)+len(bound))
+	inserted := false
+	for _, vm := range mounts {
+		if owned(vm.Name) {
+			if !inserted {
+				updated = append(updated, boundMounts...)
+				inserted = true
+			}
+			continue
+		}
+		updated = append(updated, vm)
+	}
+	if !inserted {
+		updated = append(updated, boundMounts...)
+	}
+	return updated
+}
+
+// removeBindingEntries removes the entries of the list at the path of the
+// object, volumes, volume mounts or environment variables, matched by owned
+func removeBindingEntries(obj *unstructured.Unstructured, listPath []string, owned func(string) bool) error {
+	list, found, err := unstructured.NestedSlice(obj.Object, listPath...)
+	if err != nil || !found {
+		return err
+	}
+	kept := make([]interface{}, 0, len(list))
+	for _, entry := range list {
+		if e, ok := entry.(map[string]interface{}); ok {
+			name, _ := e["name"].(string)
+			if owned(name) {
+				continue
+			}
+		}
+		kept = append(kept, entry)
+	}
+	if len(kept) == len(list) {
+		return nil
+	}
+	return unstructured.SetNestedSlice(obj.Object, kept, listPath...)
+}
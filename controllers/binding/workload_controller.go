@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// WorkloadServicesAnnotation lists the services a workload is bound to, for
+// example `postgres-main,PostgresCluster.v1beta1.postgres-operator.crunchydata.com/orders`.
+// A bare name refers to a Secret, other services are referred as
+// `<kind>.<version>.<group>/<name>`, or `<kind>.<version>/<name>` for the core group.
+const WorkloadServicesAnnotation = "binding.x-k8s.io/services"
+
+// WorkloadReconciler creates, updates and deletes the ServiceBindings declared
+// on the workloads of a kind through WorkloadServicesAnnotation.  The
+// ServiceBindings are owned by the workload and bound by the
+// ServiceBindingReconciler like any other.
+type WorkloadReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// Kind is the kind of the workloads
+	Kind schema.GroupVersionKind
+}
+
+// workloadService is a service declared on a workload
+type workloadService struct {
+	APIVersion string
+	Kind       string
+	Name       string
+}
+
+// parseWorkloadServices parses the value of WorkloadServicesAnnotation
+func parseWorkloadServices(value string) ([]workloadService, error) {
+	services := []workloadService{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, "/")
+		if i < 0 {
+			services = append(services, workloadService{APIVersion: "v1", Kind: "Secret", Name: entry})
+			continue
+		}
+		parts := strings.SplitN(entry[:i], ".", 3)
+		name := entry[i+1:]
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" || name == "" {
+			return nil, fmt.Errorf("invalid service %q, expected `<name>` or `<kind>.<version>[.<group>]/<name>`", entry)
+		}
+		gv := schema.GroupVersion{Version: parts[1]}
+		if len(parts) == 3 {
+			gv.Group = parts[2]
+		}
+		services = append(services, workloadService{APIVersion: gv.String(), Kind: parts[0], Name: name})
+	}
+	return services, nil
+}
+
+// workloadBindingName returns the name of the ServiceBinding of a service
+// declared on a workload, `<workload>-<name>` for a Secret and
+// `<workload>-<kind>-<name>` for other services.  Names too long for a
+// resource are cut and end with a hash of the full name.
+func workloadBindingName(workload string, service workloadService) string {
+	name := workload + "-" + service.Name
+	if service.Kind != "Secret" || service.APIVersion != "v1" {
+		name = workload + "-" + strings.ToLower(service.Kind) + "-" + service.Name
+	}
+	if len(name) > 253 {
+		name = strings.TrimRight(name[:253-9], "-.") + "-" + serviceHash(name)
+	}
+	return name
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=servicebindings,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile makes the ServiceBindings owned by the workload match its annotation
+func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("workload", req.NamespacedName)
+
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(r.Kind)
+	if err := r.Get(ctx, req.NamespacedName, workload); err != nil {
+		// the owned ServiceBindings of a deleted workload are garbage collected
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	services := []workloadService{}
+	if value, ok := workload.GetAnnotations()[WorkloadServicesAnnotation]; ok && workload.GetDeletionTimestamp() == nil {
+		var err error
+		services, err = parseWorkloadServices(value)
+		if err != nil {
+			// wait for the annotation to be fixed, an update triggers reconciliation
+			log.Error(err, "unable to parse the services of the workload")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	desired := map[string]workloadService{}
+	for _, service := range services {
+		name := workloadBindingName(workload.GetName(), service)
+		if declared, ok := desired[name]; ok {
+			if declared != service {
+				err := fmt.Errorf("services %s and %s map to the same ServiceBinding %q",
+					workloadServiceRef(declared), workloadServiceRef(service), name)
+				log.Error(err, "unable to declare the ServiceBinding of the workload")
+			}
+			continue
+		}
+		desired[name] = service
+		if err := r.reconcileServiceBinding(ctx, log, workload, name, service); err != nil {
+			var ownershipErr OwnershipConflictErr
+			if errors.As(err, &ownershipErr) {
+				log.Error(err, "unable to declare the ServiceBinding of the workload")
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	serviceBindings := &bindingv1beta1.ServiceBindingList{}
+	if err := r.List(ctx, serviceBindings, client.InNamespace(workload.GetNamespace())); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range serviceBindings.Items {
+		sb := &serviceBindings.Items[i]
+		if _, ok := desired[sb.Name]; ok || !metav1.IsControlledBy(sb, workload) {
+			continue
+		}
+		log.V(0).Info("deleting the ServiceBinding no longer declared by the workload", "ServiceBinding", sb.Name)
+		if err := r.Delete(ctx, sb); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// workloadServiceRef returns the service as referred in WorkloadServicesAnnotation
+func workloadServiceRef(service workloadService) string {
+	gv, _ := schema.ParseGroupVersion(service.APIVersion)
+	ref := service.Kind + "." + gv.Version
+	if gv.Group != "" {
+		ref += "." + gv.Group
+	}
+	return ref + "/" + service.Name
+}
+
+// reconcileServiceBinding creates or updates the ServiceBinding of a service
+// declared on the workload.  A ServiceBinding with the same name that is not
+// owned by the workload is never modified.
+func (r *WorkloadReconciler) reconcileServiceBinding(ctx context.Context, log logr.Logger,
+	workload *unstructured.Unstructured, name string, service workloadService) error {
+
+	sb := &bindingv1beta1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: workload.GetNamespace()}}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, sb, func() error {
+		if sb.ResourceVersion != "" && !metav1.IsControlledBy(sb, workload) {
+			return OwnershipConflictErr{Kind: "ServiceBinding", Name: sb.Name}
+		}
+		sb.Spec.Application = &bindingv1beta1.Application{
+			APIVersion: workload.GetAPIVersion(),
+			Kind:       workload.GetKind(),
+			Name:       workload.GetName(),
+		}
+		sb.Spec.Service = &bindingv1beta1.Service{
+			APIVersion: service.APIVersion,
+			Kind:       service.Kind,
+			Name:       service.Name,
+		}
+		return controllerutil.SetControllerReference(workload, sb, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.V(1).Info("ServiceBinding of the workload reconciled", "ServiceBinding", sb.Name, "operation", op)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *WorkloadReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Scheme == nil {
+		r.Scheme = mgr.GetScheme()
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(r.Kind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("workload-" + strings.ToLower(r.Kind.Kind)).
+		For(workload).
+		Owns(&bindingv1beta1.ServiceBinding{}).
+		Complete(r)
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
+)
+
+var _ = Describe("Workload Services:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When a workload declares its services with an annotation", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app23",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret23",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should create and delete the owned ServiceBinding to match the annotation", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test23",
+			}
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret23",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app23",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						bindingcontrollers.WorkloadServicesAnnotation: "secret23",
+					},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "app23-secret23", Namespace: testNamespace}
+			sb := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceBindingLookupKey, sb)
+			}, timeout, interval).Should(Succeed())
+
+			Expect(metav1.IsControlledBy(sb, app)).To(BeTrue())
+			Expect(sb.Spec.Application.Name).To(Equal("app23"))
+			Expect(sb.Spec.Service.Kind).To(Equal("Secret"))
+			Expect(sb.Spec.Service.Name).To(Equal("secret23"))
+
+			applicationLookupKey := types.NamespacedName{Name: "app23", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return len(app.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(1))
+
+			By("Removing the annotation")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return err
+				}
+				delete(app.Annotations, bindingcontrollers.WorkloadServicesAnnotation)
+				return k8sClient.Update(ctx, app)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, sb)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+	Context("When a workload declares services of several kinds with the same name", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app23b",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, name := range []string{"app23b-db23b", "app23b-service-db23b"} {
+				sb := &bindingv1beta1.ServiceBinding{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespace,
+					}}
+				err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+				Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should create a ServiceBinding per service", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test23b",
+			}
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app23b",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						bindingcontrollers.WorkloadServicesAnnotation: "db23b,Service.v1/db23b",
+					},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			secretBinding := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "app23b-db23b", Namespace: testNamespace}, secretBinding)
+			}, timeout, interval).Should(Succeed())
+			Expect(secretBinding.Spec.Service.Kind).To(Equal("Secret"))
+
+			serviceBinding := &bindingv1beta1.ServiceBinding{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: "app23b-service-db23b", Namespace: testNamespace}, serviceBinding)
+			}, timeout, interval).Should(Succeed())
+			Expect(serviceBinding.Spec.Service.Kind).To(Equal("Service"))
+		})
+	})
+	Context("When a workload declares services whose names prefix each other", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app23c",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, app, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, name := range []string{"db23c", "db23c-ro"} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespace,
+					}}
+				err := k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should keep the volumes of both services", func() {
+			ctx := context.Background()
+			matchLabels := map[string]string{
+				"environment": "test23c",
+			}
+
+			By("Creating Secrets")
+			for _, name := range []string{"db23c", "db23c-ro"} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testNamespace,
+					},
+					StringData: map[string]string{
+						"type":     "postgresql",
+						"username": name,
+					},
+				}
+				Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+			}
+
+			By("Creating Deployment")
+			app := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app23c",
+					Labels:    matchLabels,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						bindingcontrollers.WorkloadServicesAnnotation: "db23c,db23c-ro",
+					},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: matchLabels,
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: matchLabels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "ghcr.io/kubepreset/bindingdata:latest",
+								Name:  "bindingdata",
+							}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, app)).Should(Succeed())
+
+			applicationLookupKey := types.NamespacedName{Name: "app23c", Namespace: testNamespace}
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return len(app.Spec.Template.Spec.Volumes)
+			}, timeout, interval).Should(Equal(2))
+
+			By("Checking the ServiceBindings do not replace the volumes of each other")
+			generation := app.Generation
+			Consistently(func() int64 {
+				if err := k8sClient.Get(ctx, applicationLookupKey, app); err != nil {
+					return 0
+				}
+				return app.Generation
+			}, time.Second*5, interval).Should(Equal(generation))
+			Expect(app.Spec.Template.Spec.Volumes).To(HaveLen(2))
+			Expect(app.Spec.Template.Spec.Containers[0].VolumeMounts).To(HaveLen(2))
+		})
+	})
+})
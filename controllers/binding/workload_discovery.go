@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+// WorkloadMappingRegistry holds the application resource mappings derived
+// from the schemas of CustomResourceDefinitions.  It is safe for concurrent
+// use and serves the derived mappings as a ClusterApplicationResourceMappingList
+// so they can be promoted to ClusterApplicationResourceMapping resources.
+type WorkloadMappingRegistry struct {
+	mu       sync.RWMutex
+	mappings map[string]bindingv1beta1.ClusterApplicationResourceMappingSpec
+}
+
+// NewWorkloadMappingRegistry returns an empty registry
+func NewWorkloadMappingRegistry() *WorkloadMappingRegistry {
+	return &WorkloadMappingRegistry{mappings: map[string]bindingv1beta1.ClusterApplicationResourceMappingSpec{}}
+}
+
+// Register adds or replaces the mapping derived for a resource, named
+// `<resource>.<group>` like a ClusterApplicationResourceMapping
+func (r *WorkloadMappingRegistry) Register(name string, spec bindingv1beta1.ClusterApplicationResourceMappingSpec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mappings[name] = spec
+}
+
+// Remove forgets the mapping derived for a resource
+func (r *WorkloadMappingRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mappings, name)
+}
+
+// List returns the derived mappings sorted by name
+func (r *WorkloadMappingRegistry) List() []bindingv1beta1.ClusterApplicationResourceMapping {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.mappings))
+	for name := range r.mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]bindingv1beta1.ClusterApplicationResourceMapping, 0, len(names))
+	for _, name := range names {
+		spec := r.mappings[name]
+		items = append(items, bindingv1beta1.ClusterApplicationResourceMapping{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: bindingv1beta1.GroupVersion.String(),
+				Kind:       "ClusterApplicationResourceMapping",
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       *spec.DeepCopy(),
+		})
+	}
+	return items
+}
+
+// ServeHTTP writes the derived mappings as a JSON ClusterApplicationResourceMappingList
+func (r *WorkloadMappingRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	list := bindingv1beta1.ClusterApplicationResourceMappingList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: bindingv1beta1.GroupVersion.String(),
+			Kind:       "ClusterApplicationResourceMappingList",
+		},
+		Items: r.List(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(list); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// discoverWorkloadMapping derives an application resource mapping from the
+// schema of the CustomResourceDefinition of the kind
+func (r *ServiceBindingReconciler) discoverWorkloadMapping(ctx context.Context, log logr.Logger,
+	gvk schema.GroupVersionKind) (bindingv1beta1.ClusterApplicationResourceMappingSpec, bool) {
+
+	u, err := r.getCRD(ctx, gvk)
+	if err != nil || u == nil {
+		if err != nil {
+			log.V(1).Info("unable to retrieve the CustomResourceDefinition of the application", "error", err)
+		}
+		return bindingv1beta1.ClusterApplicationResourceMappingSpec{}, false
+	}
+	crd := &apixv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+		log.Error(err, "unable to convert the CustomResourceDefinition of the application")
+		return bindingv1beta1.ClusterApplicationResourceMappingSpec{}, false
+	}
+
+	spec := workloadMappingFor(crd)
+	for _, ver := range spec.Versions {
+		if ver.Version == gvk.Version {
+			log.V(1).Info("derived the application resource mapping from the CustomResourceDefinition", "mapping", ver)
+			return spec, true
+		}
+	}
+	return bindingv1beta1.ClusterApplicationResourceMappingSpec{}, false
+}
+
+// workloadMappingHandler keeps the derived mappings of the registry in sync
+// with the CustomResourceDefinitions, so they can be inspected before any
+// application of the kinds is bound
+func (r *ServiceBindingReconciler) workloadMappingHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(e event.CreateEvent, _ workqueue.RateLimitingInterface) {
+			r.registerWorkloadMapping(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+			r.registerWorkloadMapping(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+			r.Workloads.Remove(e.Object.GetName())
+		},
+	}
+}
+
+// registerWorkloadMapping registers the mapping derived from the
+// CustomResourceDefinition under its name, `<resource>.<group>`, or removes
+// it when no version embeds a pod spec
+func (r *ServiceBindingReconciler) registerWorkloadMapping(obj client.Object) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	crd := &apixv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+		r.Log.Error(err, "unable to convert the CustomResourceDefinition", "name", u.GetName())
+		return
+	}
+	spec := workloadMappingFor(crd)
+	if len(spec.Versions) == 0 {
+		r.Workloads.Remove(crd.Name)
+		return
+	}
+	r.Workloads.Register(crd.Name, spec)
+}
+
+// workloadMappingFor derives the mapping of each version of the
+// CustomResourceDefinition embedding a PodTemplateSpec or a PodSpec.  Pod
+// specs nested in arrays cannot be addressed by a mapping and are ignored.
+// When a version embeds several pod specs the shallowest one is used, as a
+// mapping has a single volumes path.
+func workloadMappingFor(crd *apixv1.CustomResourceDefinition) bindingv1beta1.ClusterApplicationResourceMappingSpec {
+	spec := bindingv1beta1.ClusterApplicationResourceMappingSpec{}
+	for _, v := range crd.Spec.Versions {
+		if !v.Served || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		paths := [][]string{}
+		findPodSpecs(*v.Schema.OpenAPIV3Schema, []string{}, &paths)
+		if len(paths) == 0 {
+			continue
+		}
+		sort.Slice(paths, func(i, j int) bool {
+			if len(paths[i]) != len(paths[j]) {
+				return len(paths[i]) < len(paths[j])
+			}
+			return strings.Join(paths[i], ".") < strings.Join(paths[j], ".")
+		})
+		podSpec := paths[0]
+		prefix := "." + strings.Join(podSpec, ".")
+		ver := bindingv1beta1.ClusterApplicationResourceMappingVersion{
+			Version:    v.Name,
+			Containers: []string{prefix + ".containers"},
+			Volumes:    prefix + ".volumes",
+		}
+		if _, ok := podSpecSchema(*v.Schema.OpenAPIV3Schema, podSpec).Properties["initContainers"]; ok {
+			ver.Containers = append(ver.Containers, prefix+".initContainers")
+		}
+		spec.Versions = append(spec.Versions, ver)
+	}
+	return spec
+}
+
+// findPodSpecs collects the paths of the objects shaped like a PodSpec
+func findPodSpecs(props apixv1.JSONSchemaProps, path []string, paths *[][]string) {
+	if isPodSpec(props) {
+		*paths = append(*paths, path)
+		return
+	}
+	for name, p := range props.Properties {
+		child := make([]string, len(path), len(path)+1)
+		copy(child, path)
+		findPodSpecs(p, append(child, name), paths)
+	}
+}
+
+// isPodSpec reports whether the schema has a `containers` array of objects
+// with a name and an image
+func isPodSpec(props apixv1.JSONSchemaProps) bool {
+	containers, ok := props.Properties["containers"]
+	if !ok || containers.Type != "array" || containers.Items == nil || containers.Items.Schema == nil {
+		return false
+	}
+	container := containers.Items.Schema.Properties
+	_, hasName := container["name"]
+	_, hasImage := container["image"]
+	return hasName && hasImage
+}
+
+func podSpecSchema(props apixv1.JSONSchemaProps, path []string) apixv1.JSONSchemaProps {
+	for _, name := range path {
+		props = props.Properties[name]
+	}
+	return props
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+)
+
+var _ = Describe("Workload Discovery:", func() {
+
+	const (
+		timeout       = time.Second * 20
+		interval      = time.Millisecond * 250
+		testNamespace = "default"
+	)
+
+	Context("When the application kind embeds a PodSpec without an application resource mapping", func() {
+
+		AfterEach(func() {
+			ctx := context.Background()
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb18",
+					Namespace: testNamespace,
+				}}
+			err := k8sClient.Delete(ctx, sb, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceBindingLookupKey := types.NamespacedName{Name: "sb18", Namespace: testNamespace}
+			deletedServiceBinding := &bindingv1beta1.ServiceBinding{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, serviceBindingLookupKey, deletedServiceBinding)
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			workerCRD := &apixv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "workers.app18.example.org",
+				}}
+			err = k8sClient.Delete(ctx, workerCRD, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret18",
+					Namespace: testNamespace,
+				}}
+			err = k8sClient.Delete(ctx, secret, client.GracePeriodSeconds(0))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should bind the application through the mapping derived from the CRD schema", func() {
+			ctx := context.Background()
+
+			By("Creating Worker CRD")
+			preserveUnknownFields := true
+			workerCRD := &apixv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "workers.app18.example.org",
+				},
+				Spec: apixv1.CustomResourceDefinitionSpec{
+					Group: "app18.example.org",
+					Versions: []apixv1.CustomResourceDefinitionVersion{{
+						Name:    "v1alpha1",
+						Served:  true,
+						Storage: true,
+						Schema: &apixv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apixv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apixv1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]apixv1.JSONSchemaProps{
+											"runner": {
+												Type: "object",
+												Properties: map[string]apixv1.JSONSchemaProps{
+													"podSpec": {
+														Type: "object",
+														Properties: map[string]apixv1.JSONSchemaProps{
+															"containers": {
+																Type: "array",
+																Items: &apixv1.JSONSchemaPropsOrArray{Schema: &apixv1.JSONSchemaProps{
+																	Type: "object",
+																	Properties: map[string]apixv1.JSONSchemaProps{
+																		"name":  {Type: "string"},
+																		"image": {Type: "string"},
+																	},
+																	XPreserveUnknownFields: &preserveUnknownFields,
+																}},
+															},
+															"volumes": {
+																Type: "array",
+																Items: &apixv1.JSONSchemaPropsOrArray{Schema: &apixv1.JSONSchemaProps{
+																	Type:                   "object",
+																	XPreserveUnknownFields: &preserveUnknownFields,
+																}},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					}},
+					Names: apixv1.CustomResourceDefinitionNames{
+						Plural: "workers",
+						Kind:   "Worker",
+					},
+					Scope: apixv1.NamespaceScoped,
+				}}
+			Expect(k8sClient.Create(ctx, workerCRD)).Should(Succeed())
+
+			workerCRDLookupKey := types.NamespacedName{Name: "workers.app18.example.org"}
+			createdWorkerCRD := &apixv1.CustomResourceDefinition{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, workerCRDLookupKey, createdWorkerCRD)
+				if err != nil {
+					return false
+				}
+				for _, condition := range createdWorkerCRD.Status.Conditions {
+					if condition.Type == apixv1.Established &&
+						condition.Status == apixv1.ConditionTrue {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+
+			By("Creating Secret")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret18",
+					Namespace: testNamespace,
+				},
+				StringData: map[string]string{
+					"type":     "custom",
+					"username": "guest",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+			By("Creating Worker CR")
+			worker := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       "Worker",
+					"apiVersion": "app18.example.org/v1alpha1",
+					"metadata": map[string]interface{}{
+						"name":      "app18",
+						"namespace": testNamespace,
+					},
+					"spec": map[string]interface{}{
+						"runner": map[string]interface{}{
+							"podSpec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{"name": "app", "image": "foo"},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, worker)).Should(Succeed())
+
+			sb := &bindingv1beta1.ServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sb18",
+					Namespace: testNamespace,
+				},
+				Spec: bindingv1beta1.ServiceBindingSpec{
+					Application: &bindingv1beta1.Application{
+						APIVersion: "app18.example.org/v1alpha1",
+						Kind:       "Worker",
+						Name:       "app18",
+					},
+					Service: &bindingv1beta1.Service{
+						APIVersion: "v1",
+						Kind:       "Secret",
+						Name:       "secret18",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, sb)).Should(Succeed())
+
+			workerLookupKey := types.NamespacedName{Name: "app18", Namespace: testNamespace}
+			Eventually(func() bool {
+				updated := &unstructured.Unstructured{}
+				updated.SetGroupVersionKind(worker.GroupVersionKind())
+				if err := k8sClient.Get(ctx, workerLookupKey, updated); err != nil {
+					return false
+				}
+				volumes, _, _ := unstructured.NestedSlice(updated.Object, "spec", "runner", "podSpec", "volumes")
+				containers, _, _ := unstructured.NestedSlice(updated.Object, "spec", "runner", "podSpec", "containers")
+				if len(volumes) != 1 || len(containers) != 1 {
+					return false
+				}
+				mounts, _, _ := unstructured.NestedSlice(containers[0].(map[string]interface{}), "volumeMounts")
+				return len(mounts) == 1
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+	Context("When a CRD embedding a PodSpec is installed", func() {
+
+		It("should serve the derived mapping before any application is bound", func() {
+			ctx := context.Background()
+
+			By("Creating Job CRD")
+			preserveUnknownFields := true
+			jobCRD := &apixv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "jobs.app18b.example.org",
+				},
+				Spec: apixv1.CustomResourceDefinitionSpec{
+					Group: "app18b.example.org",
+					Versions: []apixv1.CustomResourceDefinitionVersion{{
+						Name:    "v1",
+						Served:  true,
+						Storage: true,
+						Schema: &apixv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apixv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apixv1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]apixv1.JSONSchemaProps{
+											"containers": {
+												Type: "array",
+												Items: &apixv1.JSONSchemaPropsOrArray{Schema: &apixv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]apixv1.JSONSchemaProps{
+														"name":  {Type: "string"},
+														"image": {Type: "string"},
+													},
+													XPreserveUnknownFields: &preserveUnknownFields,
+												}},
+											},
+										},
+									},
+								},
+							},
+						},
+					}},
+					Names: apixv1.CustomResourceDefinitionNames{
+						Plural: "jobs",
+						Kind:   "Job",
+					},
+					Scope: apixv1.NamespaceScoped,
+				}}
+			Expect(k8sClient.Create(ctx, jobCRD)).Should(Succeed())
+
+			mappingNames := func() []string {
+				names := []string{}
+				for _, m := range workloads.List() {
+					names = append(names, m.Name)
+				}
+				return names
+			}
+			Eventually(mappingNames, timeout, interval).Should(ContainElement("jobs.app18b.example.org"))
+
+			for _, m := range workloads.List() {
+				if m.Name == "jobs.app18b.example.org" {
+					Expect(m.Spec.Versions).To(HaveLen(1))
+					Expect(m.Spec.Versions[0].Containers).To(Equal([]string{".spec.containers"}))
+					Expect(m.Spec.Versions[0].Volumes).To(Equal(".spec.volumes"))
+				}
+			}
+
+			By("Deleting Job CRD")
+			Expect(k8sClient.Delete(ctx, jobCRD)).Should(Succeed())
+			Eventually(mappingNames, timeout, interval).ShouldNot(ContainElement("jobs.app18b.example.org"))
+		})
+	})
+})
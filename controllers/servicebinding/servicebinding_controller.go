@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The KubePreset Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	servicebindingv1 "github.com/kubepreset/kubepreset/apis/servicebinding/v1"
+)
+
+// ServiceBindingReconciler reconciles a servicebinding.io/v1 ServiceBinding object.
+//
+// The API server only calls conversion webhooks between the versions of a
+// single CustomResourceDefinition, so `servicebinding.io/v1` cannot be
+// converted to `binding.x-k8s.io/v1beta1` on read.  Instead every v1
+// ServiceBinding is converted to an owned v1beta1 ServiceBinding with the
+// same name, which is bound by the binding controller, and the status is
+// converted back.
+type ServiceBindingReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// OwnershipConflictErr represents the error when the v1beta1 ServiceBinding
+// with the name of the v1 ServiceBinding is not owned by it
+type OwnershipConflictErr struct {
+	Name string
+}
+
+func (e OwnershipConflictErr) Error() string {
+	return fmt.Sprintf("binding.x-k8s.io/v1beta1 ServiceBinding %q exists and is not owned by the servicebinding.io/v1 ServiceBinding", e.Name)
+}
+
+// +kubebuilder:rbac:groups=servicebinding.io,resources=servicebindings,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=servicebinding.io,resources=servicebindings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=binding.x-k8s.io,resources=servicebindings,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile mirrors the v1 ServiceBinding into a v1beta1 ServiceBinding
+func (r *ServiceBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("servicebinding", req.NamespacedName)
+
+	sb := &servicebindingv1.ServiceBinding{}
+	if err := r.Get(ctx, req.NamespacedName, sb); err != nil {
+		log.V(1).Info("unable to fetch servicebinding.io/v1 ServiceBinding", "error", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	desired := &bindingv1beta1.ServiceBinding{}
+	if err := sb.ConvertTo(desired); err != nil {
+		log.Error(err, "unable to convert the ServiceBinding to binding.x-k8s.io/v1beta1")
+		return ctrl.Result{}, err
+	}
+
+	hub := &bindingv1beta1.ServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: sb.Name, Namespace: sb.Namespace}}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, hub, func() error {
+		if hub.ResourceVersion != "" && !metav1.IsControlledBy(hub, sb) {
+			return OwnershipConflictErr{Name: hub.Name}
+		}
+		hub.Labels = desired.Labels
+		hub.Spec = desired.Spec
+		return controllerutil.SetControllerReference(sb, hub, r.Scheme)
+	})
+	if err != nil {
+		var conflict OwnershipConflictErr
+		if errors.As(err, &conflict) {
+			log.Error(err, "unable to mirror the ServiceBinding")
+			return ctrl.Result{}, r.setStatus(ctx, sb, servicebindingv1.ServiceBindingStatus{
+				ObservedGeneration: sb.Generation,
+				Conditions: []metav1.Condition{{
+					Type:               string(bindingv1beta1.ConditionReady),
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: sb.Generation,
+					LastTransitionTime: metav1.Now(),
+					Reason:             "OwnershipConflict",
+					Message:            conflict.Error(),
+				}},
+			})
+		}
+		log.Error(err, "unable to create or update the binding.x-k8s.io/v1beta1 ServiceBinding")
+		return ctrl.Result{}, err
+	}
+	log.V(1).Info("binding.x-k8s.io/v1beta1 ServiceBinding reconciled", "operation", op)
+
+	converted := &servicebindingv1.ServiceBinding{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		log.Error(err, "unable to convert the status from binding.x-k8s.io/v1beta1")
+		return ctrl.Result{}, err
+	}
+	converted.Status.ObservedGeneration = sb.Generation
+	for i := range converted.Status.Conditions {
+		converted.Status.Conditions[i].ObservedGeneration = sb.Generation
+	}
+	return ctrl.Result{}, r.setStatus(ctx, sb, converted.Status)
+}
+
+// setStatus updates the status of the v1 ServiceBinding when it changed
+func (r *ServiceBindingReconciler) setStatus(ctx context.Context, sb *servicebindingv1.ServiceBinding,
+	status servicebindingv1.ServiceBindingStatus) error {
+
+	for i, c := range status.Conditions {
+		// keep the transition time stable unless the status changed
+		for _, existing := range sb.Status.Conditions {
+			if existing.Type == c.Type && existing.Status == c.Status {
+				status.Conditions[i].LastTransitionTime = existing.LastTransitionTime
+			}
+		}
+	}
+	if equality.Semantic.DeepEqual(sb.Status, status) {
+		return nil
+	}
+	sb.Status = status
+	return r.Status().Update(ctx, sb)
+}
+
+// SetupWithManager setup controller with manager
+func (r *ServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Scheme == nil {
+		r.Scheme = mgr.GetScheme()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&servicebindingv1.ServiceBinding{}).
+		Owns(&bindingv1beta1.ServiceBinding{}).
+		Complete(r)
+}
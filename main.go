@@ -19,23 +19,32 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	bindingv1beta1 "github.com/kubepreset/kubepreset/apis/binding/v1beta1"
+	servicebindingv1 "github.com/kubepreset/kubepreset/apis/servicebinding/v1"
 	bindingcontrollers "github.com/kubepreset/kubepreset/controllers/binding"
+	servicebindingcontrollers "github.com/kubepreset/kubepreset/controllers/servicebinding"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -48,6 +57,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(bindingv1beta1.AddToScheme(scheme))
+	utilruntime.Must(servicebindingv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -55,11 +65,26 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var clusterDomain string
+	var builtinMappings string
+	var auditOnly bool
+	var enablePodWebhook bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&clusterDomain, "cluster-domain", bindingcontrollers.DefaultClusterDomain,
+		"The DNS domain of the cluster used for the host of bound Services.")
+	flag.StringVar(&builtinMappings, "builtin-mappings", "",
+		"Comma separated entries of the built-in application resource mapping catalog to enable, or all. "+
+			"Available entries: "+strings.Join(bindingcontrollers.CatalogEntries(), ", ")+".")
+	flag.BoolVar(&auditOnly, "audit-only", false,
+		"Resolve and validate every ServiceBinding and report the applications it would update, "+
+			"without updating them, as if every ServiceBinding were suspended.")
+	flag.BoolVar(&enablePodWebhook, "enable-pod-webhook", false,
+		"Serve the Pod mutating webhook injecting the ServiceBindings in Pod mode into the Pods of their applications. "+
+			"ServiceBindings in Pod mode are not Ready without it.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -75,6 +100,10 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "df3e393f.x-k8s.io",
+		// refresh the REST mappings lazily so kinds installed after startup can be bound
+		MapperProvider: func(c *rest.Config) (meta.RESTMapper, error) {
+			return apiutil.NewDynamicRESTMapper(c, apiutil.WithLazyDiscovery)
+		},
 		NewCache: cache.BuilderWithOptions(cache.Options{
 			SelectorsByObject: cache.SelectorsByObject{
 				&corev1.Secret{}: {
@@ -89,14 +118,74 @@ func main() {
 		os.Exit(1)
 	}
 
+	var entries []string
+	if builtinMappings != "" {
+		entries = strings.Split(builtinMappings, ",")
+	}
+	catalog, err := bindingcontrollers.NewMappingCatalog(entries...)
+	if err != nil {
+		setupLog.Error(err, "unable to load the built-in mapping catalog")
+		os.Exit(1)
+	}
+	workloads := bindingcontrollers.NewWorkloadMappingRegistry()
+	if err := mgr.AddMetricsExtraHandler("/debug/workload-mappings", workloads); err != nil {
+		setupLog.Error(err, "unable to serve the derived application resource mappings")
+		os.Exit(1)
+	}
 	if err = (&bindingcontrollers.ServiceBindingReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Log:           ctrl.Log.WithName("bindingcontrollers.servicebinding").WithName("ServiceBinding"),
+		Workloads:     workloads,
+		Catalog:       catalog,
+		ClusterDomain: clusterDomain,
+		AuditOnly:     auditOnly,
+		PodWebhook:    enablePodWebhook,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ServiceBinding")
+		os.Exit(1)
+	}
+	if err = (&bindingcontrollers.ClusterServiceBindingReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("bindingcontrollers.servicebinding").WithName("ServiceBinding"),
+		Log:    ctrl.Log.WithName("bindingcontrollers.clusterservicebinding").WithName("ClusterServiceBinding"),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "ServiceBinding")
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterServiceBinding")
 		os.Exit(1)
 	}
+	for _, kind := range []schema.GroupVersionKind{
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		appsv1.SchemeGroupVersion.WithKind("StatefulSet"),
+		appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+	} {
+		if err = (&bindingcontrollers.WorkloadReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Log:    ctrl.Log.WithName("bindingcontrollers.workload").WithName(kind.Kind),
+			Kind:   kind,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Workload", "kind", kind.Kind)
+			os.Exit(1)
+		}
+	}
+	if err = (&servicebindingcontrollers.ServiceBindingReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Log:    ctrl.Log.WithName("servicebindingcontrollers.servicebinding").WithName("ServiceBinding"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "servicebinding.io/v1 ServiceBinding")
+		os.Exit(1)
+	}
+	if enablePodWebhook {
+		mgr.GetWebhookServer().Register(bindingcontrollers.PodBindingWebhookPath, &webhook.Admission{
+			Handler: &bindingcontrollers.PodBinder{
+				Client:    mgr.GetClient(),
+				APIReader: mgr.GetAPIReader(),
+				Log:       ctrl.Log.WithName("bindingcontrollers.podbinder").WithName("Pod"),
+				AuditOnly: auditOnly,
+			},
+		})
+	}
 	/*
 		if err = (&bindingv1beta1.ServiceBinding{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ServiceBinding")